@@ -0,0 +1,96 @@
+// Package testpb provides a typed protobuf event envelope for tests. It
+// mirrors the platform event layout with typed fields (timestamps, nested
+// device and order messages) so decoders are exercised against a real
+// message schema instead of a google.protobuf.Struct.
+package testpb
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var eventDescriptor = buildEventDescriptor()
+
+// NewEvent returns an empty eventlib.test.Event message.
+func NewEvent() proto.Message {
+	return dynamicpb.NewMessage(eventDescriptor)
+}
+
+func buildEventDescriptor() protoreflect.MessageDescriptor {
+	file := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("eventlib/test/event.proto"),
+		Package:    proto.String("eventlib.test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/struct.proto", "google/protobuf/timestamp.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			message("Event",
+				scalar("id", 1),
+				scalar("status", 2),
+				typed("created_at", 3, ".google.protobuf.Timestamp"),
+				scalar("type", 4),
+				scalar("category", 5),
+				scalar("sub", 6),
+				typed("attributes", 7, ".eventlib.test.Attributes"),
+			),
+			message("Attributes",
+				typed("device", 1, ".eventlib.test.Device"),
+				typed("data", 2, ".google.protobuf.Struct"),
+				typed("order", 3, ".eventlib.test.Order"),
+			),
+			message("Device",
+				scalar("id", 1),
+				scalar("correlation_id", 2),
+				scalar("uid", 3),
+				scalar("account_id", 4),
+				repeated(scalar("orders", 5)),
+			),
+			message("Order",
+				scalar("id", 1),
+				scalar("correlation_id", 2),
+				scalar("group", 3),
+				scalar("status", 4),
+				scalar("type", 5),
+				typed("created_at", 6, ".google.protobuf.Timestamp"),
+				typed("updated_at", 7, ".google.protobuf.Timestamp"),
+			),
+		},
+	}
+
+	fd, err := protodesc.NewFile(file, protoregistry.GlobalFiles)
+	if err != nil {
+		panic("testpb: " + err.Error())
+	}
+	return fd.Messages().ByName("Event")
+}
+
+func message(name string, fields ...*descriptorpb.FieldDescriptorProto) *descriptorpb.DescriptorProto {
+	return &descriptorpb.DescriptorProto{Name: proto.String(name), Field: fields}
+}
+
+func scalar(name string, number int32) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		JsonName: proto.String(name),
+		Number:   proto.Int32(number),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+	}
+}
+
+func typed(name string, number int32, typeName string) *descriptorpb.FieldDescriptorProto {
+	field := scalar(name, number)
+	field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	field.TypeName = proto.String(typeName)
+	return field
+}
+
+func repeated(field *descriptorpb.FieldDescriptorProto) *descriptorpb.FieldDescriptorProto {
+	field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	return field
+}
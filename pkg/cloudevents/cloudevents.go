@@ -0,0 +1,272 @@
+package cloudevents
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-eventlib/pkg/types/base"
+)
+
+const (
+	SpecVersion        = "1.0"
+	TypePrefix         = "br.com.v3"
+	DefaultSource      = "urn:v3:events"
+	AccountIDExtension = "v3accountid"
+
+	ContentTypeJSON       = "application/json"
+	ContentTypeStructured = "application/cloudevents+json"
+)
+
+var ErrInvalidEvent = errors.New("cloudevents: invalid event")
+
+type Event struct {
+	ID              string
+	Source          string
+	SpecVersion     string
+	Type            string
+	Subject         string
+	Time            time.Time
+	DataContentType string
+	Data            json.RawMessage
+	Extensions      map[string]string
+}
+
+type Option func(*Event)
+
+func WithSource(source string) Option {
+	return func(e *Event) {
+		e.Source = source
+	}
+}
+
+func WithExtension(name, value string) Option {
+	return func(e *Event) {
+		e.Extensions[name] = value
+	}
+}
+
+func ToCloudEvent(event *base.BaseEvent, opts ...Option) (*Event, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("cloudevents: encoding event %s: %w", event.GetID(), err)
+	}
+
+	ce := &Event{
+		ID:              event.GetID(),
+		Source:          DefaultSource,
+		SpecVersion:     SpecVersion,
+		Type:            TypeOf(event),
+		Subject:         event.GetDeviceID(),
+		Time:            event.GetCreatedAt(),
+		DataContentType: ContentTypeJSON,
+		Data:            data,
+		Extensions:      map[string]string{},
+	}
+
+	if accountID := event.GetAccountID(); accountID != "" {
+		ce.Extensions[AccountIDExtension] = accountID
+	}
+
+	for _, opt := range opts {
+		opt(ce)
+	}
+
+	return ce, nil
+}
+
+func FromCloudEvent(ce *Event) (*base.BaseEvent, error) {
+	if err := ce.Validate(); err != nil {
+		return nil, err
+	}
+
+	var event base.BaseEvent
+	if len(ce.Data) > 0 {
+		if err := json.Unmarshal(ce.Data, &event); err != nil {
+			return nil, fmt.Errorf("%w: decoding data: %v", ErrInvalidEvent, err)
+		}
+	}
+
+	if event.ID == "" {
+		event.ID = ce.ID
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = ce.Time
+	}
+	if event.Attributes.Device == nil && (ce.Subject != "" || ce.Extensions[AccountIDExtension] != "") {
+		event.Attributes.Device = &base.Device{
+			ID:        ce.Subject,
+			AccountID: ce.Extensions[AccountIDExtension],
+		}
+	}
+
+	return &event, nil
+}
+
+func TypeOf(event *base.BaseEvent) string {
+	category := strings.TrimPrefix(string(event.GetCategory()), "EVENT_CATEGORY_")
+
+	name := event.GetEventName()
+	if name == "" {
+		name = strings.TrimPrefix(string(event.GetSubType()), "EVENT_SUB_")
+	}
+
+	parts := []string{TypePrefix}
+	for _, part := range []string{category, name} {
+		if part != "" {
+			parts = append(parts, strings.ToLower(part))
+		}
+	}
+
+	return strings.Join(parts, ".")
+}
+
+func (e *Event) Validate() error {
+	switch {
+	case e.SpecVersion != SpecVersion:
+		return fmt.Errorf("%w: unsupported specversion %q", ErrInvalidEvent, e.SpecVersion)
+	case e.ID == "":
+		return fmt.Errorf("%w: missing id", ErrInvalidEvent)
+	case e.Source == "":
+		return fmt.Errorf("%w: missing source", ErrInvalidEvent)
+	case e.Type == "":
+		return fmt.Errorf("%w: missing type", ErrInvalidEvent)
+	}
+	return nil
+}
+
+func (e *Event) MarshalJSON() ([]byte, error) {
+	out := map[string]interface{}{
+		"specversion": e.SpecVersion,
+		"id":          e.ID,
+		"source":      e.Source,
+		"type":        e.Type,
+	}
+	if e.Subject != "" {
+		out["subject"] = e.Subject
+	}
+	if !e.Time.IsZero() {
+		out["time"] = e.Time.Format(time.RFC3339Nano)
+	}
+	if e.DataContentType != "" {
+		out["datacontenttype"] = e.DataContentType
+	}
+	if len(e.Data) > 0 {
+		out["data"] = e.Data
+	}
+	for name, value := range e.Extensions {
+		out[name] = value
+	}
+
+	return json.Marshal(out)
+}
+
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*e = Event{Extensions: map[string]string{}}
+
+	for name, value := range raw {
+		switch name {
+		case "data":
+			e.Data = value
+			continue
+		case "data_base64":
+			var s string
+			if err := json.Unmarshal(value, &s); err != nil {
+				return fmt.Errorf("%w: data_base64: %v", ErrInvalidEvent, err)
+			}
+			decoded, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return fmt.Errorf("%w: data_base64: %v", ErrInvalidEvent, err)
+			}
+			e.Data = decoded
+			continue
+		}
+
+		s, ok, err := attributeValue(name, value)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err := e.setAttribute(name, s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// attributeValue returns the canonical string form of a JSON attribute.
+// Context attributes are strings, while extensions may also be integers or
+// booleans, which are kept in their decimal and "true"/"false" forms. A null
+// attribute is treated as absent.
+func attributeValue(name string, value json.RawMessage) (string, bool, error) {
+	if string(value) == "null" {
+		return "", false, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		return s, true, nil
+	} else if isContextAttribute(name) {
+		return "", false, fmt.Errorf("%w: attribute %s: %v", ErrInvalidEvent, name, err)
+	}
+
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return strconv.FormatBool(b), true, nil
+	}
+
+	var n int32
+	if err := json.Unmarshal(value, &n); err != nil {
+		return "", false, fmt.Errorf("%w: extension %s must be a string, integer or boolean", ErrInvalidEvent, name)
+	}
+	return strconv.FormatInt(int64(n), 10), true, nil
+}
+
+func isContextAttribute(name string) bool {
+	switch name {
+	case "specversion", "id", "source", "type", "subject", "datacontenttype", "dataschema", "time":
+		return true
+	}
+	return false
+}
+
+func (e *Event) setAttribute(name, value string) error {
+	switch name {
+	case "specversion":
+		e.SpecVersion = value
+	case "id":
+		e.ID = value
+	case "source":
+		e.Source = value
+	case "type":
+		e.Type = value
+	case "subject":
+		e.Subject = value
+	case "datacontenttype":
+		e.DataContentType = value
+	case "time":
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return fmt.Errorf("%w: time: %v", ErrInvalidEvent, err)
+		}
+		e.Time = t
+	default:
+		if e.Extensions == nil {
+			e.Extensions = map[string]string{}
+		}
+		e.Extensions[name] = value
+	}
+	return nil
+}
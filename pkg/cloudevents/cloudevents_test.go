@@ -0,0 +1,237 @@
+package cloudevents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-eventlib/pkg/types/base"
+)
+
+func loadFixture(t *testing.T, file string) *base.BaseEvent {
+	t.Helper()
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("erro ao ler %s: %v", file, err)
+	}
+	var event base.BaseEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		t.Fatalf("erro ao decodificar %s: %v", file, err)
+	}
+	return &event
+}
+
+func TestToCloudEvent(t *testing.T) {
+	event := loadFixture(t, "../../test/events/dms-events/vision-drowsiness.json")
+
+	ce, err := ToCloudEvent(event, WithSource("urn:v3:webhook"))
+	if err != nil {
+		t.Fatalf("ToCloudEvent() erro inesperado: %v", err)
+	}
+
+	if ce.ID != "01KCHNFZWN4YPSM0A4YFMH09T2" {
+		t.Errorf("ID = %s, esperava 01KCHNFZWN4YPSM0A4YFMH09T2", ce.ID)
+	}
+	if ce.Type != "br.com.v3.dms.drowsiness" {
+		t.Errorf("Type = %s, esperava br.com.v3.dms.drowsiness", ce.Type)
+	}
+	if ce.Subject != "01KBZJ4WWBE5N78S4F3DASW1WJ" {
+		t.Errorf("Subject = %s, esperava 01KBZJ4WWBE5N78S4F3DASW1WJ", ce.Subject)
+	}
+	if ce.Extensions[AccountIDExtension] != "01GZXXCVVPEKM7E830XAMJKA14" {
+		t.Errorf("extensão %s = %s, esperava 01GZXXCVVPEKM7E830XAMJKA14", AccountIDExtension, ce.Extensions[AccountIDExtension])
+	}
+	if !ce.Time.Equal(event.CreatedAt) {
+		t.Errorf("Time = %v, esperava %v", ce.Time, event.CreatedAt)
+	}
+	if ce.Source != "urn:v3:webhook" {
+		t.Errorf("Source = %s, esperava urn:v3:webhook", ce.Source)
+	}
+}
+
+func TestTypeOf_FallsBackToSub(t *testing.T) {
+	event := &base.BaseEvent{
+		Category: "EVENT_CATEGORY_ORDER",
+		Sub:      "EVENT_SUB_ORDER_STATUS",
+	}
+
+	if got := TypeOf(event); got != "br.com.v3.order.order_status" {
+		t.Errorf("TypeOf() = %s, esperava br.com.v3.order.order_status", got)
+	}
+}
+
+func TestRoundTrip_Fixtures(t *testing.T) {
+	files, err := filepath.Glob("../../test/events/*/*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("nenhum fixture encontrado: %v", err)
+	}
+
+	for _, mode := range []Mode{ModeBinary, ModeStructured} {
+		for _, file := range files {
+			event := loadFixture(t, file)
+
+			ce, err := ToCloudEvent(event)
+			if err != nil {
+				t.Fatalf("ToCloudEvent(%s) erro inesperado: %v", file, err)
+			}
+
+			req, err := NewRequest(context.Background(), "http://localhost/events", ce, mode)
+			if err != nil {
+				t.Fatalf("NewRequest(%s) erro inesperado: %v", file, err)
+			}
+
+			decoded, err := FromRequest(req)
+			if err != nil {
+				t.Fatalf("FromRequest(%s) erro inesperado: %v", file, err)
+			}
+			if decoded.Type != ce.Type || decoded.Subject != ce.Subject || !decoded.Time.Equal(ce.Time) {
+				t.Errorf("%s (modo %d): atributos diferem após o transporte HTTP", file, mode)
+			}
+
+			back, err := FromCloudEvent(decoded)
+			if err != nil {
+				t.Fatalf("FromCloudEvent(%s) erro inesperado: %v", file, err)
+			}
+
+			want, _ := json.Marshal(event)
+			got, _ := json.Marshal(back)
+			if !bytes.Equal(want, got) {
+				t.Errorf("%s (modo %d): evento convertido difere do original", file, mode)
+			}
+		}
+	}
+}
+
+func TestEncode_BinaryHeaders(t *testing.T) {
+	ce := &Event{
+		ID:              "event-1",
+		Source:          DefaultSource,
+		SpecVersion:     SpecVersion,
+		Type:            "br.com.v3.vision.face_lost",
+		Subject:         "device-1",
+		Time:            time.Date(2025, 12, 15, 19, 0, 0, 0, time.UTC),
+		DataContentType: ContentTypeJSON,
+		Data:            json.RawMessage(`{"id":"event-1"}`),
+		Extensions:      map[string]string{AccountIDExtension: "account-1"},
+	}
+
+	header, body, err := Encode(ce, ModeBinary)
+	if err != nil {
+		t.Fatalf("Encode() erro inesperado: %v", err)
+	}
+
+	expected := map[string]string{
+		"ce-specversion": "1.0",
+		"ce-id":          "event-1",
+		"ce-type":        "br.com.v3.vision.face_lost",
+		"ce-subject":     "device-1",
+		"ce-time":        "2025-12-15T19:00:00Z",
+		"ce-v3accountid": "account-1",
+		"Content-Type":   ContentTypeJSON,
+	}
+	for name, want := range expected {
+		if got := header.Get(name); got != want {
+			t.Errorf("header %s = %s, esperava %s", name, got, want)
+		}
+	}
+	if string(body) != `{"id":"event-1"}` {
+		t.Errorf("body = %s, esperava os dados do evento", body)
+	}
+}
+
+func TestStructuredJSON(t *testing.T) {
+	data := []byte(`{
+		"specversion": "1.0",
+		"id": "event-1",
+		"source": "urn:v3:events",
+		"type": "br.com.v3.dms.drowsiness",
+		"subject": "device-1",
+		"time": "2025-12-15T19:02:27.853477693Z",
+		"v3accountid": "account-1",
+		"datacontenttype": "application/json",
+		"data": {"category": "EVENT_CATEGORY_DMS"}
+	}`)
+
+	var ce Event
+	if err := json.Unmarshal(data, &ce); err != nil {
+		t.Fatalf("Unmarshal() erro inesperado: %v", err)
+	}
+
+	event, err := FromCloudEvent(&ce)
+	if err != nil {
+		t.Fatalf("FromCloudEvent() erro inesperado: %v", err)
+	}
+
+	if event.ID != "event-1" || event.GetDeviceID() != "device-1" || event.GetAccountID() != "account-1" {
+		t.Errorf("evento = %+v, esperava id, dispositivo e conta preenchidos a partir dos atributos", event)
+	}
+	if event.Category != "EVENT_CATEGORY_DMS" {
+		t.Errorf("Category = %s, esperava EVENT_CATEGORY_DMS", event.Category)
+	}
+	if event.CreatedAt.Nanosecond() != 853477693 {
+		t.Errorf("CreatedAt = %v, esperava nanossegundos preservados", event.CreatedAt)
+	}
+}
+
+func TestStructuredJSON_ExtensionTypesAndBase64Data(t *testing.T) {
+	data := []byte(`{
+		"specversion": "1.0",
+		"id": "event-1",
+		"source": "urn:v3:events",
+		"type": "br.com.v3.dms.drowsiness",
+		"v3accountid": "account-1",
+		"v3retries": 3,
+		"v3replay": true,
+		"v3trace": null,
+		"datacontenttype": "application/json",
+		"data_base64": "eyJjYXRlZ29yeSI6ICJFVkVOVF9DQVRFR09SWV9ETVMifQ=="
+	}`)
+
+	var ce Event
+	if err := json.Unmarshal(data, &ce); err != nil {
+		t.Fatalf("Unmarshal() erro inesperado: %v", err)
+	}
+
+	want := map[string]string{AccountIDExtension: "account-1", "v3retries": "3", "v3replay": "true"}
+	if len(ce.Extensions) != len(want) {
+		t.Errorf("Extensions = %v, esperava %v", ce.Extensions, want)
+	}
+	for name, value := range want {
+		if ce.Extensions[name] != value {
+			t.Errorf("extensão %s = %q, esperava %q", name, ce.Extensions[name], value)
+		}
+	}
+
+	event, err := FromCloudEvent(&ce)
+	if err != nil {
+		t.Fatalf("FromCloudEvent() erro inesperado: %v", err)
+	}
+	if event.Category != "EVENT_CATEGORY_DMS" {
+		t.Errorf("Category = %s, esperava EVENT_CATEGORY_DMS", event.Category)
+	}
+
+	invalid := []string{
+		`{"specversion": "1.0", "id": 1, "source": "s", "type": "t"}`,
+		`{"specversion": "1.0", "id": "1", "source": "s", "type": "t", "v3ratio": 0.5}`,
+		`{"specversion": "1.0", "id": "1", "source": "s", "type": "t", "data_base64": "%%%"}`,
+	}
+	for _, data := range invalid {
+		if err := json.Unmarshal([]byte(data), &ce); !errors.Is(err, ErrInvalidEvent) {
+			t.Errorf("Unmarshal(%s) erro = %v, esperava ErrInvalidEvent", data, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if _, err := FromCloudEvent(&Event{SpecVersion: "0.3", ID: "x", Source: "s", Type: "t"}); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("FromCloudEvent() = %v, esperava ErrInvalidEvent", err)
+	}
+	if _, err := Decode(nil, nil); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("Decode() sem atributos = %v, esperava ErrInvalidEvent", err)
+	}
+}
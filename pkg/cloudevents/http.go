@@ -0,0 +1,113 @@
+package cloudevents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type Mode int
+
+const (
+	ModeBinary Mode = iota
+	ModeStructured
+)
+
+const headerPrefix = "Ce-"
+
+func NewRequest(ctx context.Context, url string, ce *Event, mode Mode) (*http.Request, error) {
+	header, body, err := Encode(ce, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	return req, nil
+}
+
+func Encode(ce *Event, mode Mode) (http.Header, []byte, error) {
+	if err := ce.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	header := http.Header{}
+
+	if mode == ModeStructured {
+		body, err := json.Marshal(ce)
+		if err != nil {
+			return nil, nil, err
+		}
+		header.Set("Content-Type", ContentTypeStructured)
+		return header, body, nil
+	}
+
+	header.Set(headerPrefix+"Specversion", ce.SpecVersion)
+	header.Set(headerPrefix+"Id", ce.ID)
+	header.Set(headerPrefix+"Source", ce.Source)
+	header.Set(headerPrefix+"Type", ce.Type)
+	if ce.Subject != "" {
+		header.Set(headerPrefix+"Subject", ce.Subject)
+	}
+	if !ce.Time.IsZero() {
+		header.Set(headerPrefix+"Time", ce.Time.Format(time.RFC3339Nano))
+	}
+	for name, value := range ce.Extensions {
+		header.Set(headerPrefix+name, value)
+	}
+	if ce.DataContentType != "" {
+		header.Set("Content-Type", ce.DataContentType)
+	}
+
+	return header, ce.Data, nil
+}
+
+func FromRequest(r *http.Request) (*Event, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(r.Header, body)
+}
+
+func Decode(header http.Header, body []byte) (*Event, error) {
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+
+	if mediaType == ContentTypeStructured {
+		var ce Event
+		if err := json.Unmarshal(body, &ce); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+		}
+		return &ce, ce.Validate()
+	}
+
+	ce := &Event{Extensions: map[string]string{}}
+	for name, values := range header {
+		if len(values) == 0 || !strings.HasPrefix(http.CanonicalHeaderKey(name), headerPrefix) {
+			continue
+		}
+		attribute := strings.ToLower(strings.TrimPrefix(http.CanonicalHeaderKey(name), headerPrefix))
+		if err := ce.setAttribute(attribute, values[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	ce.DataContentType = header.Get("Content-Type")
+	if len(body) > 0 {
+		ce.Data = body
+	}
+
+	return ce, ce.Validate()
+}
@@ -0,0 +1,153 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"go-eventlib/pkg/types/base"
+)
+
+type Format string
+
+const (
+	FormatJSON     Format = "json"
+	FormatProtobuf Format = "protobuf"
+)
+
+type Codec struct {
+	newMessage func() proto.Message
+}
+
+func New(newMessage func() proto.Message) *Codec {
+	return &Codec{newMessage: newMessage}
+}
+
+// Encode re-encodes event through the message returned by newMessage.
+// Typed fields follow the message (field order, timestamps, enums). JSON
+// output keeps google.protobuf.Struct, Value and ListValue fields as they
+// appear in event, so data decoded with Decode keeps its key order and
+// number formatting (0.90 stays 0.90). Binary protobuf carries Struct
+// numbers as doubles and cannot keep their formatting.
+func (c *Codec) Encode(event *base.BaseEvent, format Format) ([]byte, error) {
+	msg, err := c.toMessage(event)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatJSON:
+		data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+		if err != nil {
+			return nil, fmt.Errorf("codec: encoding event %s: %w", event.GetID(), err)
+		}
+		source, err := json.Marshal(event)
+		if err != nil {
+			return nil, fmt.Errorf("codec: encoding event %s: %w", event.GetID(), err)
+		}
+		if data, err = preserve(msg.ProtoReflect().Descriptor(), data, source); err != nil {
+			return nil, fmt.Errorf("codec: encoding event %s: %w", event.GetID(), err)
+		}
+
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, data); err != nil {
+			return nil, fmt.Errorf("codec: encoding event %s: %w", event.GetID(), err)
+		}
+		return buf.Bytes(), nil
+	case FormatProtobuf:
+		data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+		if err != nil {
+			return nil, fmt.Errorf("codec: encoding event %s: %w", event.GetID(), err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("codec: unsupported format %q", format)
+	}
+}
+
+func (c *Codec) Decode(data []byte, format Format) (*base.BaseEvent, error) {
+	var jsonData []byte
+
+	switch format {
+	case FormatJSON:
+		jsonData = data
+	case FormatProtobuf:
+		msg := c.newMessage()
+		if err := proto.Unmarshal(data, msg); err != nil {
+			return nil, fmt.Errorf("codec: decoding protobuf: %w", err)
+		}
+
+		var err error
+		if jsonData, err = (protojson.MarshalOptions{UseProtoNames: true}).Marshal(msg); err != nil {
+			return nil, fmt.Errorf("codec: decoding protobuf: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("codec: unsupported format %q", format)
+	}
+
+	var event base.BaseEvent
+	if err := json.Unmarshal(jsonData, &event); err != nil {
+		return nil, fmt.Errorf("codec: decoding json: %w", err)
+	}
+	if err := keepRawData(&event, jsonData); err != nil {
+		return nil, fmt.Errorf("codec: decoding json: %w", err)
+	}
+	return &event, nil
+}
+
+// keepRawData replaces the decoded data groups of event with their raw
+// JSON, so Encode can write them back unchanged.
+func keepRawData(event *base.BaseEvent, data []byte) error {
+	if event.Attributes.Data == nil {
+		return nil
+	}
+
+	var raw struct {
+		Attributes struct {
+			Data struct {
+				Telemetry       json.RawMessage `json:"telemetry"`
+				StandaloneEvent json.RawMessage `json:"standalone_event"`
+				TripEvent       json.RawMessage `json:"trip_event"`
+			} `json:"data"`
+		} `json:"attributes"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	d := event.Attributes.Data
+	for _, field := range []struct {
+		dst *interface{}
+		raw json.RawMessage
+	}{
+		{&d.Telemetry, raw.Attributes.Data.Telemetry},
+		{&d.StandaloneEvent, raw.Attributes.Data.StandaloneEvent},
+		{&d.TripEvent, raw.Attributes.Data.TripEvent},
+	} {
+		if *field.dst == nil {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, field.raw); err != nil {
+			return err
+		}
+		*field.dst = json.RawMessage(buf.Bytes())
+	}
+	return nil
+}
+
+func (c *Codec) toMessage(event *base.BaseEvent) (proto.Message, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("codec: encoding event %s: %w", event.GetID(), err)
+	}
+
+	msg := c.newMessage()
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("codec: encoding event %s: %w", event.GetID(), err)
+	}
+	return msg, nil
+}
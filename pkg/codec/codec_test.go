@@ -0,0 +1,138 @@
+package codec
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-eventlib/internal/testpb"
+	"go-eventlib/pkg/types/base"
+)
+
+var update = flag.Bool("update", false, "atualiza os arquivos golden")
+
+func newCodec() *Codec {
+	return New(testpb.NewEvent)
+}
+
+func fixtures(t *testing.T) []string {
+	t.Helper()
+	files, err := filepath.Glob("../../test/events/*/*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("nenhum fixture encontrado: %v", err)
+	}
+	return files
+}
+
+func decodeFixture(t *testing.T, file string) *base.BaseEvent {
+	t.Helper()
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("erro ao ler %s: %v", file, err)
+	}
+	event, err := newCodec().Decode(data, FormatJSON)
+	if err != nil {
+		t.Fatalf("erro ao decodificar %s: %v", file, err)
+	}
+	return event
+}
+
+func TestEncode_Golden(t *testing.T) {
+	c := newCodec()
+
+	for _, file := range fixtures(t) {
+		got, err := c.Encode(decodeFixture(t, file), FormatJSON)
+		if err != nil {
+			t.Fatalf("Encode(%s) erro inesperado: %v", file, err)
+		}
+
+		golden := filepath.Join("testdata", "golden", strings.TrimSuffix(filepath.Base(file), ".json")+".golden.json")
+		if *update {
+			if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(golden, append(got, '\n'), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("erro ao ler golden %s: %v", golden, err)
+		}
+		if !bytes.Equal(got, bytes.TrimSuffix(want, []byte("\n"))) {
+			t.Errorf("%s: saída difere do golden %s", file, golden)
+		}
+	}
+}
+
+func TestEncode_RoundTripIsStable(t *testing.T) {
+	c := newCodec()
+
+	for _, format := range []Format{FormatJSON, FormatProtobuf} {
+		for _, file := range fixtures(t) {
+			original := decodeFixture(t, file)
+
+			first, err := c.Encode(original, format)
+			if err != nil {
+				t.Fatalf("Encode(%s, %s) erro inesperado: %v", file, format, err)
+			}
+
+			decoded, err := c.Decode(first, format)
+			if err != nil {
+				t.Fatalf("Decode(%s, %s) erro inesperado: %v", file, format, err)
+			}
+			if decoded.ID != original.ID || !decoded.CreatedAt.Equal(original.CreatedAt) || decoded.GetEventName() != original.GetEventName() {
+				t.Errorf("%s (%s): evento decodificado difere do original", file, format)
+			}
+
+			second, err := c.Encode(decoded, format)
+			if err != nil {
+				t.Fatalf("Encode(%s, %s) erro inesperado: %v", file, format, err)
+			}
+			if !bytes.Equal(first, second) {
+				t.Errorf("%s (%s): codificação não é estável após decode→encode", file, format)
+			}
+
+			redecoded, err := c.Decode(second, format)
+			if err != nil {
+				t.Fatalf("Decode(%s, %s) erro inesperado: %v", file, format, err)
+			}
+			if !reflect.DeepEqual(decoded, redecoded) {
+				t.Errorf("%s (%s): decode→encode→decode não é estável", file, format)
+			}
+		}
+	}
+}
+
+func TestEncode_PreservesDataRepresentation(t *testing.T) {
+	c := newCodec()
+	payload := []byte(`{"id":"event-1","created_at":"2025-12-15T19:02:27Z","attributes":{"data":{"trip_event":{"speed":0.90,"b":1,"a":{"z":1e3,"y":[2.50]}}}}}`)
+
+	event, err := c.Decode(payload, FormatJSON)
+	if err != nil {
+		t.Fatalf("Decode() erro inesperado: %v", err)
+	}
+	encoded, err := c.Encode(event, FormatJSON)
+	if err != nil {
+		t.Fatalf("Encode() erro inesperado: %v", err)
+	}
+	want := `"data":{"trip_event":{"speed":0.90,"b":1,"a":{"z":1e3,"y":[2.50]}}}`
+	if !bytes.Contains(encoded, []byte(want)) {
+		t.Errorf("Encode() = %s, esperava os dados como no original: %s", encoded, want)
+	}
+}
+
+func TestEncode_UnsupportedFormat(t *testing.T) {
+	if _, err := newCodec().Encode(&base.BaseEvent{ID: "event-1"}, Format("xml")); err == nil {
+		t.Error("Encode() com formato xml deveria retornar erro")
+	}
+	if _, err := newCodec().Decode([]byte("{}"), Format("xml")); err == nil {
+		t.Error("Decode() com formato xml deveria retornar erro")
+	}
+}
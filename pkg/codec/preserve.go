@@ -0,0 +1,104 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// preserve rewrites the protojson encoding data of a desc message so that
+// every google.protobuf.Struct, Value and ListValue field holds the JSON
+// found at the same path in source. Other fields keep their protojson form.
+func preserve(desc protoreflect.MessageDescriptor, data, source []byte) ([]byte, error) {
+	obj, err := parseObject(data)
+	if err != nil {
+		return nil, err
+	}
+	src, err := parseObject(source)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, m := range obj {
+		field := desc.Fields().ByName(protoreflect.Name(m.key))
+		if field == nil {
+			field = desc.Fields().ByJSONName(m.key)
+		}
+		value, ok := src.get(m.key)
+		if field == nil || field.Message() == nil || field.IsList() || field.IsMap() || !ok {
+			continue
+		}
+
+		switch field.Message().FullName() {
+		case "google.protobuf.Struct", "google.protobuf.Value", "google.protobuf.ListValue":
+			obj[i].value = value
+		default:
+			if len(value) == 0 || value[0] != '{' {
+				continue
+			}
+			if value, err = preserve(field.Message(), m.value, value); err != nil {
+				return nil, err
+			}
+			obj[i].value = value
+		}
+	}
+	return obj.marshal(), nil
+}
+
+type member struct {
+	key   string
+	value json.RawMessage
+}
+
+// object is a JSON object that keeps its key order.
+type object []member
+
+func parseObject(data []byte) (object, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+
+	var obj object
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		obj = append(obj, member{key: key, value: value})
+	}
+	return obj, nil
+}
+
+func (o object) get(key string) (json.RawMessage, bool) {
+	for _, m := range o {
+		if m.key == key {
+			return m.value, true
+		}
+	}
+	return nil, false
+}
+
+func (o object) marshal() []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(m.key)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(m.value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
@@ -0,0 +1,366 @@
+package devicestate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/common"
+	"go-eventlib/pkg/types/hardware"
+	"go-eventlib/pkg/types/telemetry"
+)
+
+const (
+	StateOnline  = "ONLINE"
+	StateOffline = "OFFLINE"
+)
+
+type Battery struct {
+	Status  string
+	Voltage float64
+}
+
+type Snapshot struct {
+	DeviceID       string
+	AccountID      string
+	State          string
+	Ignition       telemetry.IgnitionStatus
+	Location       *common.Coordinates
+	FixTime        time.Time
+	DeviceBattery  Battery
+	VehicleBattery Battery
+	WiFi           string
+	SIMCard        string
+	SDCard         string
+	Model          string
+	Vendor         string
+	Firmware       string
+	LastSeen       time.Time
+}
+
+func (s Snapshot) Online() bool { return s.State == StateOnline }
+
+func (s Snapshot) FixAge(now time.Time) (time.Duration, bool) {
+	if s.FixTime.IsZero() {
+		return 0, false
+	}
+	return now.Sub(s.FixTime), true
+}
+
+type Change struct {
+	Old    Snapshot
+	New    Snapshot
+	Fields []string
+}
+
+type Tracker struct {
+	mu      sync.RWMutex
+	devices map[string]*Snapshot
+
+	subMu       sync.RWMutex
+	subscribers map[int]func(Change)
+	nextSub     int
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		devices:     make(map[string]*Snapshot),
+		subscribers: make(map[int]func(Change)),
+	}
+}
+
+func (t *Tracker) Update(event *base.BaseEvent) *Change {
+	deviceID := event.GetDeviceID()
+	if deviceID == "" {
+		return nil
+	}
+
+	t.mu.Lock()
+	current, ok := t.devices[deviceID]
+	if !ok {
+		current = &Snapshot{DeviceID: deviceID}
+		t.devices[deviceID] = current
+	}
+	if event.GetCreatedAt().Before(current.LastSeen) {
+		t.mu.Unlock()
+		return nil
+	}
+
+	old := *current
+	apply(current, event)
+	fields := diff(old, *current)
+	updated := *current
+	t.mu.Unlock()
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	change := &Change{Old: old, New: updated, Fields: fields}
+	t.notify(*change)
+	return change
+}
+
+func (t *Tracker) Get(deviceID string) (Snapshot, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	snapshot, ok := t.devices[deviceID]
+	if !ok {
+		return Snapshot{}, false
+	}
+	return *snapshot, true
+}
+
+func (t *Tracker) All() []Snapshot {
+	t.mu.RLock()
+	snapshots := make([]Snapshot, 0, len(t.devices))
+	for _, snapshot := range t.devices {
+		snapshots = append(snapshots, *snapshot)
+	}
+	t.mu.RUnlock()
+
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].DeviceID < snapshots[j].DeviceID })
+	return snapshots
+}
+
+func (t *Tracker) Subscribe(fn func(Change)) (unsubscribe func()) {
+	t.subMu.Lock()
+	id := t.nextSub
+	t.nextSub++
+	t.subscribers[id] = fn
+	t.subMu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.subMu.Lock()
+			delete(t.subscribers, id)
+			t.subMu.Unlock()
+		})
+	}
+}
+
+func (t *Tracker) Handler() dispatch.Handler {
+	return func(ctx context.Context, event *base.BaseEvent) error {
+		t.Update(event)
+		return nil
+	}
+}
+
+func (t *Tracker) notify(change Change) {
+	t.subMu.RLock()
+	ids := make([]int, 0, len(t.subscribers))
+	for id := range t.subscribers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	subscribers := make([]func(Change), 0, len(ids))
+	for _, id := range ids {
+		subscribers = append(subscribers, t.subscribers[id])
+	}
+	t.subMu.RUnlock()
+
+	for _, fn := range subscribers {
+		fn(change)
+	}
+}
+
+type detail struct {
+	EventName   string `json:"event_name"`
+	DeviceState *struct {
+		State string `json:"state"`
+	} `json:"device_state"`
+	WiFi *struct {
+		Status string `json:"status"`
+	} `json:"wifi_connection"`
+	SIMCard *struct {
+		Status string `json:"status"`
+	} `json:"sim_card"`
+}
+
+func apply(s *Snapshot, event *base.BaseEvent) {
+	if accountID := event.GetAccountID(); accountID != "" {
+		s.AccountID = accountID
+	}
+	if createdAt := event.GetCreatedAt(); createdAt.After(s.LastSeen) && event.Type != "EVENT_TYPE_DERIVED" {
+		s.LastSeen = createdAt
+	}
+
+	if coords := event.GetCoordinates(); coords != nil && (coords.Latitude != 0 || coords.Longitude != 0) {
+		location := *coords
+		s.Location = &location
+		if fix, ok := event.GetFixTime(); ok {
+			s.FixTime = fix
+		} else {
+			s.FixTime = event.GetCreatedAt()
+		}
+	}
+
+	wrapped := telemetry.New(event)
+	if data := wrapped.GetTelemetryData(); data != nil {
+		applyTelemetry(s, data)
+	}
+	if ignition := wrapped.GetIgnitionStatus(); ignition != "" {
+		s.Ignition = ignition
+	}
+	if battery := wrapped.GetBatteryEvent(); battery != nil && battery.Status != "" {
+		switch battery.Component {
+		case "BATTERY_COMPONENT_DEVICE":
+			s.DeviceBattery.Status = battery.Status
+		case "BATTERY_COMPONENT_VEHICLE":
+			s.VehicleBattery.Status = battery.Status
+		}
+	}
+
+	d := eventDetail(event)
+	if d == nil {
+		return
+	}
+
+	switch d.EventName {
+	case "DEVICE_STATE":
+		if d.DeviceState != nil && d.DeviceState.State != "" {
+			s.State = strings.TrimPrefix(d.DeviceState.State, "DEVICE_STATE_")
+		}
+	case "DEVICE_SILENT":
+		s.State = StateOffline
+	case "DEVICE_RESUMED":
+		s.State = StateOnline
+	case "WIFI_CONNECTED", "WIFI_DISCONNECTED":
+		s.WiFi = strings.TrimPrefix(d.EventName, "WIFI_")
+		if d.WiFi != nil && d.WiFi.Status != "" {
+			s.WiFi = d.WiFi.Status
+		}
+	case "SIMCARD":
+		if d.SIMCard != nil && d.SIMCard.Status != "" {
+			s.SIMCard = strings.TrimPrefix(d.SIMCard.Status, "SIM_CARD_STATUS_")
+		}
+	case "SIM_CARD_INSERTED":
+		s.SIMCard = "PRESENT"
+	case "SIM_CARD_REMOVED":
+		s.SIMCard = "ABSENT"
+	case "SD_CARD_MOUNTED", "SD_CARD_UNMOUNTED":
+		s.SDCard = strings.TrimPrefix(d.EventName, "SD_CARD_")
+	}
+}
+
+func applyTelemetry(s *Snapshot, data *telemetry.Telemetry) {
+	if data.Status != "" {
+		s.Ignition = data.Status
+	}
+
+	for _, metric := range data.Metrics {
+		if metric == nil {
+			continue
+		}
+		var battery *Battery
+		switch metric.Component {
+		case "BATTERY_COMPONENT_DEVICE":
+			battery = &s.DeviceBattery
+		case "BATTERY_COMPONENT_VEHICLE":
+			battery = &s.VehicleBattery
+		default:
+			continue
+		}
+		if metric.Status != "" {
+			battery.Status = metric.Status
+		}
+		if metric.Voltage != 0 {
+			battery.Voltage = metric.Voltage
+		}
+	}
+
+	var hw hardware.Hardware
+	if data.Hardware != nil && remarshal(data.Hardware, &hw) {
+		if hw.Model != nil && (hw.Model.Name != "" || hw.Model.Vendor != "") {
+			s.Model = hw.Model.Name
+			s.Vendor = hw.Model.Vendor
+		}
+	}
+
+	firmware := hw.FirmwareVersion
+	if firmware == nil && data.FirmwareVersion != nil {
+		firmware = &hardware.FirmwareVersion{}
+		if !remarshal(data.FirmwareVersion, firmware) {
+			firmware = nil
+		}
+	}
+	if version := formatFirmware(firmware); version != "" {
+		s.Firmware = version
+	}
+}
+
+func formatFirmware(firmware *hardware.FirmwareVersion) string {
+	if firmware == nil {
+		return ""
+	}
+
+	var parts []string
+	for _, key := range []string{"major", "minor", "patch"} {
+		if value, ok := firmware.Version[key]; ok {
+			parts = append(parts, fmt.Sprint(value))
+		}
+	}
+	version := strings.Join(parts, ".")
+
+	switch {
+	case firmware.Name != "" && version != "":
+		return firmware.Name + " " + version
+	case firmware.Name != "":
+		return firmware.Name
+	default:
+		return version
+	}
+}
+
+func eventDetail(event *base.BaseEvent) *detail {
+	var d detail
+	if group := event.GetEventGroup(); group == nil || !remarshal(group, &d) || d.EventName == "" {
+		return nil
+	}
+	return &d
+}
+
+func diff(old, updated Snapshot) []string {
+	var fields []string
+	add := func(changed bool, name string) {
+		if changed {
+			fields = append(fields, name)
+		}
+	}
+
+	add(old.AccountID != updated.AccountID, "account_id")
+	add(old.State != updated.State, "state")
+	add(old.Ignition != updated.Ignition, "ignition")
+	add(!sameLocation(old.Location, updated.Location), "location")
+	add(old.DeviceBattery != updated.DeviceBattery, "device_battery")
+	add(old.VehicleBattery != updated.VehicleBattery, "vehicle_battery")
+	add(old.WiFi != updated.WiFi, "wifi")
+	add(old.SIMCard != updated.SIMCard, "sim_card")
+	add(old.SDCard != updated.SDCard, "sd_card")
+	add(old.Model != updated.Model || old.Vendor != updated.Vendor, "model")
+	add(old.Firmware != updated.Firmware, "firmware")
+	return fields
+}
+
+func sameLocation(a, b *common.Coordinates) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func remarshal(in interface{}, out interface{}) bool {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, out) == nil
+}
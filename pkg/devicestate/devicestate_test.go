@@ -0,0 +1,213 @@
+package devicestate
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"go-eventlib/pkg/eventio"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/telemetry"
+)
+
+var start = time.Date(2025, 12, 15, 19, 0, 0, 0, time.UTC)
+
+func fixture(t *testing.T, name string, offset time.Duration) *base.BaseEvent {
+	t.Helper()
+
+	for event, err := range eventio.ReadFile("../../test/events/" + name) {
+		if err != nil {
+			t.Fatalf("ReadFile(%s) erro inesperado: %v", name, err)
+		}
+		event.Attributes.Device.ID = "device-1"
+		event.CreatedAt = start.Add(offset)
+		return event
+	}
+	t.Fatalf("ReadFile(%s) não retornou eventos", name)
+	return nil
+}
+
+func TestTracker_BuildsSnapshotFromFixtures(t *testing.T) {
+	tracker := NewTracker()
+	for i, name := range []string{
+		"hardware-events/hardware-device-state.json",
+		"telemetry-events/telemetry-vehicle-battery.json",
+		"hardware-events/hardware-wifi-disconnected.json",
+		"hardware-events/hardware-simcard-removed.json",
+		"hardware-events/hardware-sdcard-mounted.json",
+		"telemetry-events/vehicle-ignition-off.json",
+	} {
+		tracker.Update(fixture(t, name, time.Duration(i)*time.Minute))
+	}
+
+	got, ok := tracker.Get("device-1")
+	if !ok {
+		t.Fatal("Get() não encontrou device-1")
+	}
+	if !got.Online() {
+		t.Errorf("State = %q, esperava ONLINE", got.State)
+	}
+	if got.Ignition != telemetry.IgnitionStatusOff {
+		t.Errorf("Ignition = %q, esperava IGNITION_STATUS_OFF", got.Ignition)
+	}
+	if got.VehicleBattery.Status != "BATTERY_OFFLINE" || got.VehicleBattery.Voltage != 12.8 {
+		t.Errorf("VehicleBattery = %+v", got.VehicleBattery)
+	}
+	if got.DeviceBattery.Voltage != 4.2 {
+		t.Errorf("DeviceBattery = %+v", got.DeviceBattery)
+	}
+	if got.WiFi != "DISCONNECTED" || got.SIMCard != "ABSENT" || got.SDCard != "MOUNTED" {
+		t.Errorf("WiFi = %q, SIMCard = %q, SDCard = %q", got.WiFi, got.SIMCard, got.SDCard)
+	}
+	// The last telemetry block only reports the vendor; an empty firmware
+	// block keeps the version seen earlier.
+	if got.Model != "" || got.Vendor != "jimi-iot" || got.Firmware != "v3-firmware 2.1.0" {
+		t.Errorf("Model = %q, Vendor = %q, Firmware = %q", got.Model, got.Vendor, got.Firmware)
+	}
+	if got.Location == nil || got.Location.Latitude != -23.55052 {
+		t.Errorf("Location = %+v, esperava a posição do evento de bateria", got.Location)
+	}
+	if !got.LastSeen.Equal(start.Add(5 * time.Minute)) {
+		t.Errorf("LastSeen = %v, esperava %v", got.LastSeen, start.Add(5*time.Minute))
+	}
+}
+
+func TestTracker_LocationFixAge(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Update(fixture(t, "telemetry-events/telemetry-ignition.json", 0))
+
+	got, _ := tracker.Get("device-1")
+	if got.Location == nil {
+		t.Fatal("Location = nil, esperava coordenadas")
+	}
+	age, ok := got.FixAge(got.FixTime.Add(30 * time.Second))
+	if !ok || age != 30*time.Second {
+		t.Errorf("FixAge() = %v, %v, esperava 30s", age, ok)
+	}
+	if _, ok := (Snapshot{}).FixAge(start); ok {
+		t.Error("FixAge() sem posição retornou ok")
+	}
+}
+
+func TestTracker_NotifiesSubscribersOnChange(t *testing.T) {
+	tracker := NewTracker()
+
+	var changes []Change
+	unsubscribe := tracker.Subscribe(func(change Change) { changes = append(changes, change) })
+
+	tracker.Update(fixture(t, "hardware-events/hardware-wifi-connected.json", 0))
+	tracker.Update(fixture(t, "hardware-events/hardware-wifi-connected.json", time.Minute))
+	tracker.Update(fixture(t, "hardware-events/hardware-wifi-disconnected.json", 2*time.Minute))
+
+	if len(changes) != 2 {
+		t.Fatalf("len(changes) = %d, esperava 2 (estado inicial e Wi-Fi): %+v", len(changes), changes)
+	}
+	last := changes[len(changes)-1]
+	if last.Old.WiFi != "CONNECTED" || last.New.WiFi != "DISCONNECTED" || !contains(last.Fields, "wifi") {
+		t.Errorf("última mudança = %+v", last)
+	}
+
+	unsubscribe()
+	unsubscribe()
+	tracker.Update(fixture(t, "hardware-events/hardware-wifi-connected.json", 3*time.Minute))
+	if len(changes) != 2 {
+		t.Errorf("assinante removido recebeu %d mudanças", len(changes)-2)
+	}
+}
+
+func TestTracker_IgnoresOutOfOrderEvents(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Update(fixture(t, "hardware-events/hardware-sdcard-unmounted.json", time.Minute))
+
+	if change := tracker.Update(fixture(t, "hardware-events/hardware-sdcard-mounted.json", 0)); change != nil {
+		t.Errorf("evento atrasado gerou mudança %+v", change)
+	}
+	if got, _ := tracker.Get("device-1"); got.SDCard != "UNMOUNTED" {
+		t.Errorf("SDCard = %q, esperava UNMOUNTED", got.SDCard)
+	}
+}
+
+func TestTracker_WatchdogEventsSetState(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Update(fixture(t, "hardware-events/hardware-device-state.json", 0))
+
+	silent := &base.BaseEvent{
+		ID:        "watchdog-device-1",
+		CreatedAt: start.Add(time.Hour),
+		Type:      "EVENT_TYPE_DERIVED",
+		Attributes: base.Attributes{
+			Device: &base.Device{ID: "device-1"},
+			Data: &base.Data{StandaloneEvent: map[string]interface{}{
+				"system": map[string]interface{}{"event_name": "DEVICE_SILENT"},
+			}},
+		},
+	}
+	change := tracker.Update(silent)
+	if change == nil || change.New.Online() || change.New.State != StateOffline {
+		t.Fatalf("Update(DEVICE_SILENT) = %+v, esperava OFFLINE", change)
+	}
+	if !change.New.LastSeen.Equal(start) {
+		t.Errorf("LastSeen = %v, evento derivado não deveria avançar", change.New.LastSeen)
+	}
+}
+
+func TestTracker_UsesNamedEventGroup(t *testing.T) {
+	event := &base.BaseEvent{
+		ID:        "event-1",
+		CreatedAt: start,
+		Attributes: base.Attributes{
+			Device: &base.Device{ID: "device-1"},
+			Data: &base.Data{StandaloneEvent: map[string]interface{}{
+				"event_group_name": "SYSTEM",
+				"hardware":         map[string]interface{}{"event_name": "SD_CARD_UNMOUNTED"},
+				"system":           map[string]interface{}{"event_name": "DEVICE_SILENT"},
+			}},
+		},
+	}
+
+	for i := 0; i < 20; i++ {
+		tracker := NewTracker()
+		tracker.Update(event)
+		if got, _ := tracker.Get("device-1"); got.State != StateOffline || got.SDCard != "" {
+			t.Fatalf("snapshot = %+v, esperava apenas o grupo SYSTEM", got)
+		}
+	}
+}
+
+func TestTracker_ConcurrentReads(t *testing.T) {
+	tracker := NewTracker()
+	events := []*base.BaseEvent{
+		fixture(t, "hardware-events/hardware-sdcard-mounted.json", 0),
+		fixture(t, "hardware-events/hardware-sdcard-unmounted.json", time.Minute),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for _, event := range events {
+				tracker.Update(event)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			tracker.Get("device-1")
+			tracker.All()
+		}()
+	}
+	wg.Wait()
+
+	if all := tracker.All(); len(all) != 1 || all[0].DeviceID != "device-1" {
+		t.Errorf("All() = %+v", all)
+	}
+}
+
+func contains(fields []string, name string) bool {
+	for _, field := range fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
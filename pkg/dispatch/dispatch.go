@@ -0,0 +1,109 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"runtime"
+	"sync"
+
+	"go-eventlib/pkg/types/base"
+)
+
+type Handler func(ctx context.Context, event *base.BaseEvent) error
+
+var ErrClosed = errors.New("dispatch: dispatcher closed")
+
+type Config struct {
+	Lanes       int
+	QueueSize   int
+	Middlewares []Middleware
+	OnError     func(ctx context.Context, event *base.BaseEvent, err error)
+}
+
+type job struct {
+	ctx   context.Context
+	event *base.BaseEvent
+}
+
+type Partitioned struct {
+	handler Handler
+	onError func(ctx context.Context, event *base.BaseEvent, err error)
+	lanes   []chan job
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func NewPartitioned(handler Handler, cfg Config) *Partitioned {
+	if cfg.Lanes <= 0 {
+		cfg.Lanes = runtime.GOMAXPROCS(0)
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 64
+	}
+
+	p := &Partitioned{
+		handler: Chain(handler, cfg.Middlewares...),
+		onError: cfg.OnError,
+		lanes:   make([]chan job, cfg.Lanes),
+	}
+
+	for i := range p.lanes {
+		p.lanes[i] = make(chan job, cfg.QueueSize)
+		p.wg.Add(1)
+		go p.run(p.lanes[i])
+	}
+
+	return p
+}
+
+func (p *Partitioned) Submit(ctx context.Context, event *base.BaseEvent) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+
+	j := job{ctx: context.WithoutCancel(ctx), event: event}
+
+	select {
+	case p.lanes[laneOf(event.GetDeviceID(), len(p.lanes))] <- j:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *Partitioned) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	for _, lane := range p.lanes {
+		close(lane)
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+func laneOf(deviceID string, lanes int) int {
+	h := fnv.New32a()
+	h.Write([]byte(deviceID))
+	return int(h.Sum32() % uint32(lanes))
+}
+
+func (p *Partitioned) run(lane <-chan job) {
+	defer p.wg.Done()
+
+	for j := range lane {
+		if err := p.handler(j.ctx, j.event); err != nil && p.onError != nil {
+			p.onError(j.ctx, j.event, err)
+		}
+	}
+}
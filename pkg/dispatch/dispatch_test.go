@@ -0,0 +1,157 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go-eventlib/pkg/types/base"
+)
+
+func newEvent(deviceID string, seq int) *base.BaseEvent {
+	return &base.BaseEvent{
+		ID:         fmt.Sprintf("%s-%d", deviceID, seq),
+		Category:   base.EventCategory("EVENT_CATEGORY_VISION"),
+		Attributes: base.Attributes{Device: &base.Device{ID: deviceID}},
+	}
+}
+
+func TestPartitioned_PreservesOrderPerDevice(t *testing.T) {
+	const devices = 50
+	const perDevice = 200
+
+	var mu sync.Mutex
+	seen := make(map[string][]string)
+
+	p := NewPartitioned(func(ctx context.Context, event *base.BaseEvent) error {
+		mu.Lock()
+		seen[event.GetDeviceID()] = append(seen[event.GetDeviceID()], event.ID)
+		mu.Unlock()
+		return nil
+	}, Config{Lanes: 8, QueueSize: 4})
+
+	var wg sync.WaitGroup
+	for d := 0; d < devices; d++ {
+		wg.Add(1)
+		go func(deviceID string) {
+			defer wg.Done()
+			for i := 0; i < perDevice; i++ {
+				if err := p.Submit(context.Background(), newEvent(deviceID, i)); err != nil {
+					t.Errorf("Submit() erro inesperado: %v", err)
+				}
+			}
+		}(fmt.Sprintf("device-%d", d))
+	}
+	wg.Wait()
+	p.Close()
+
+	if len(seen) != devices {
+		t.Fatalf("dispositivos processados = %d, esperava %d", len(seen), devices)
+	}
+
+	for deviceID, ids := range seen {
+		if len(ids) != perDevice {
+			t.Fatalf("%s: eventos processados = %d, esperava %d", deviceID, len(ids), perDevice)
+		}
+		for i, id := range ids {
+			if want := fmt.Sprintf("%s-%d", deviceID, i); id != want {
+				t.Fatalf("%s: evento %d = %s, esperava %s", deviceID, i, id, want)
+			}
+		}
+	}
+}
+
+func TestPartitioned_FaceDetectedBeforeFaceLost(t *testing.T) {
+	var mu sync.Mutex
+	var order []base.EventSub
+
+	p := NewPartitioned(func(ctx context.Context, event *base.BaseEvent) error {
+		if event.Sub == "FACE_DETECTED" {
+			time.Sleep(10 * time.Millisecond)
+		}
+		mu.Lock()
+		order = append(order, event.Sub)
+		mu.Unlock()
+		return nil
+	}, Config{Lanes: 4})
+
+	detected := newEvent("device-1", 0)
+	detected.Sub = "FACE_DETECTED"
+	lost := newEvent("device-1", 1)
+	lost.Sub = "FACE_LOST"
+
+	p.Submit(context.Background(), detected)
+	p.Submit(context.Background(), lost)
+	p.Close()
+
+	if len(order) != 2 || order[0] != "FACE_DETECTED" || order[1] != "FACE_LOST" {
+		t.Errorf("ordem = %v, esperava [FACE_DETECTED FACE_LOST]", order)
+	}
+}
+
+func TestPartitioned_DevicesRunInParallel(t *testing.T) {
+	const lanes = 4
+
+	blocked := "device-a"
+	free := ""
+	for i := 0; free == ""; i++ {
+		if id := fmt.Sprintf("device-%d", i); laneOf(id, lanes) != laneOf(blocked, lanes) {
+			free = id
+		}
+	}
+
+	release := make(chan struct{})
+	done := make(chan struct{})
+	p := NewPartitioned(func(ctx context.Context, event *base.BaseEvent) error {
+		if event.GetDeviceID() == blocked {
+			<-release
+			return nil
+		}
+		close(done)
+		return nil
+	}, Config{Lanes: lanes})
+	defer p.Close()
+
+	p.Submit(context.Background(), newEvent(blocked, 0))
+	p.Submit(context.Background(), newEvent(free, 0))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("evento de outro dispositivo ficou bloqueado pelo primeiro")
+	}
+	close(release)
+}
+
+func TestPartitioned_ReportsHandlerErrors(t *testing.T) {
+	handlerErr := errors.New("falha")
+	var got error
+
+	p := NewPartitioned(func(ctx context.Context, event *base.BaseEvent) error {
+		return handlerErr
+	}, Config{
+		Lanes: 1,
+		OnError: func(ctx context.Context, event *base.BaseEvent, err error) {
+			got = err
+		},
+	})
+
+	p.Submit(context.Background(), newEvent("device-1", 0))
+	p.Close()
+
+	if !errors.Is(got, handlerErr) {
+		t.Errorf("OnError recebeu %v, esperava %v", got, handlerErr)
+	}
+}
+
+func TestPartitioned_SubmitAfterClose(t *testing.T) {
+	p := NewPartitioned(func(ctx context.Context, event *base.BaseEvent) error { return nil }, Config{})
+	p.Close()
+
+	if err := p.Submit(context.Background(), newEvent("device-1", 0)); !errors.Is(err, ErrClosed) {
+		t.Errorf("Submit() = %v, esperava ErrClosed", err)
+	}
+}
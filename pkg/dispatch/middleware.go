@@ -0,0 +1,110 @@
+package dispatch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"go-eventlib/pkg/types/base"
+)
+
+type Middleware func(next Handler) Handler
+
+func Chain(handler Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+func ForCategory(category base.EventCategory, middlewares ...Middleware) Middleware {
+	return func(next Handler) Handler {
+		wrapped := Chain(next, middlewares...)
+		return func(ctx context.Context, event *base.BaseEvent) error {
+			if event.GetCategory() == category {
+				return wrapped(ctx, event)
+			}
+			return next(ctx, event)
+		}
+	}
+}
+
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("dispatch: handler panic: %v", e.Value)
+}
+
+func Recover() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, event *base.BaseEvent) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = &PanicError{Value: r, Stack: debug.Stack()}
+				}
+			}()
+			return next(ctx, event)
+		}
+	}
+}
+
+// Timeout returns once the handler finishes or its context expires. The
+// handler runs in its own goroutine and keeps running after a timeout until
+// it honors ctx, so it may overlap a retry or a later event of the same
+// device. A panic in the handler is returned as a *PanicError.
+func Timeout(timeout time.Duration) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, event *base.BaseEvent) error {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			done := make(chan error, 1)
+			go func() {
+				defer func() {
+					if r := recover(); r != nil {
+						done <- &PanicError{Value: r, Stack: debug.Stack()}
+					}
+				}()
+				done <- next(ctx, event)
+			}()
+
+			select {
+			case err := <-done:
+				return err
+			case <-ctx.Done():
+				return fmt.Errorf("dispatch: handler for event %s: %w", event.GetID(), ctx.Err())
+			}
+		}
+	}
+}
+
+func Logging(logger *slog.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, event *base.BaseEvent) error {
+			start := time.Now()
+			err := next(ctx, event)
+
+			attrs := []slog.Attr{
+				slog.String("event_id", event.GetID()),
+				slog.String("device_id", event.GetDeviceID()),
+				slog.String("category", string(event.GetCategory())),
+				slog.String("event_name", event.GetEventName()),
+				slog.Duration("duration", time.Since(start)),
+			}
+
+			if err != nil {
+				attrs = append(attrs, slog.Any("error", err))
+				logger.LogAttrs(ctx, slog.LevelError, "event handler failed", attrs...)
+				return err
+			}
+
+			logger.LogAttrs(ctx, slog.LevelInfo, "event handled", attrs...)
+			return nil
+		}
+	}
+}
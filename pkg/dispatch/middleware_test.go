@@ -0,0 +1,160 @@
+package dispatch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"go-eventlib/pkg/types/base"
+)
+
+func TestChain_Order(t *testing.T) {
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, event *base.BaseEvent) error {
+				calls = append(calls, name)
+				return next(ctx, event)
+			}
+		}
+	}
+
+	handler := Chain(func(ctx context.Context, event *base.BaseEvent) error {
+		calls = append(calls, "handler")
+		return nil
+	}, record("first"), record("second"))
+
+	handler(context.Background(), &base.BaseEvent{})
+
+	if strings.Join(calls, ",") != "first,second,handler" {
+		t.Errorf("ordem = %v, esperava [first second handler]", calls)
+	}
+}
+
+func TestForCategory(t *testing.T) {
+	applied := 0
+	counter := func(next Handler) Handler {
+		return func(ctx context.Context, event *base.BaseEvent) error {
+			applied++
+			return next(ctx, event)
+		}
+	}
+
+	handler := Chain(func(ctx context.Context, event *base.BaseEvent) error { return nil },
+		ForCategory(base.EventCategory("EVENT_CATEGORY_DMS"), counter))
+
+	handler(context.Background(), &base.BaseEvent{Category: "EVENT_CATEGORY_DMS"})
+	handler(context.Background(), &base.BaseEvent{Category: "EVENT_CATEGORY_VISION"})
+
+	if applied != 1 {
+		t.Errorf("middleware aplicado %d vezes, esperava 1", applied)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	handler := Chain(func(ctx context.Context, event *base.BaseEvent) error {
+		panic("boom")
+	}, Recover())
+
+	err := handler(context.Background(), &base.BaseEvent{})
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("erro = %v, esperava *PanicError", err)
+	}
+	if panicErr.Value != "boom" {
+		t.Errorf("PanicError.Value = %v, esperava boom", panicErr.Value)
+	}
+	if !bytes.Contains(panicErr.Stack, []byte("TestRecover")) {
+		t.Error("PanicError.Stack não contém o stack trace do handler")
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	handler := Chain(func(ctx context.Context, event *base.BaseEvent) error {
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	}, Timeout(10*time.Millisecond))
+
+	err := handler(context.Background(), &base.BaseEvent{ID: "event-1"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("erro = %v, esperava context.DeadlineExceeded", err)
+	}
+}
+
+func TestTimeout_RecoversPanic(t *testing.T) {
+	handler := Chain(func(ctx context.Context, event *base.BaseEvent) error {
+		panic("boom")
+	}, Recover(), Timeout(time.Second))
+
+	err := handler(context.Background(), &base.BaseEvent{ID: "event-1"})
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || panicErr.Value != "boom" {
+		t.Fatalf("erro = %v, esperava *PanicError", err)
+	}
+}
+
+func TestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	handler := Chain(func(ctx context.Context, event *base.BaseEvent) error {
+		return errors.New("falha")
+	}, Logging(logger))
+
+	event := &base.BaseEvent{
+		ID:       "event-1",
+		Category: "EVENT_CATEGORY_DMS",
+		Attributes: base.Attributes{
+			Device: &base.Device{ID: "device-1"},
+			Data: &base.Data{
+				TripEvent: map[string]interface{}{
+					"trip_id": "trip-1",
+					"dms":     map[string]interface{}{"event_name": "DROWSINESS"},
+				},
+			},
+		},
+	}
+	handler(context.Background(), event)
+
+	out := buf.String()
+	for _, want := range []string{
+		`"level":"ERROR"`,
+		`"event_id":"event-1"`,
+		`"device_id":"device-1"`,
+		`"category":"EVENT_CATEGORY_DMS"`,
+		`"event_name":"DROWSINESS"`,
+		`"error":"falha"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log %s não contém %s", out, want)
+		}
+	}
+}
+
+func TestPartitioned_AppliesMiddlewares(t *testing.T) {
+	var got error
+	p := NewPartitioned(func(ctx context.Context, event *base.BaseEvent) error {
+		panic("boom")
+	}, Config{
+		Lanes:       1,
+		Middlewares: []Middleware{Recover()},
+		OnError: func(ctx context.Context, event *base.BaseEvent, err error) {
+			got = err
+		},
+	})
+
+	p.Submit(context.Background(), &base.BaseEvent{ID: "event-1"})
+	p.Close()
+
+	var panicErr *PanicError
+	if !errors.As(got, &panicErr) {
+		t.Errorf("OnError recebeu %v, esperava *PanicError", got)
+	}
+}
@@ -0,0 +1,147 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go-eventlib/pkg/types/base"
+)
+
+// Filter selects events by account, device, category and event name. Empty
+// fields match everything; all non-empty fields must match.
+type Filter struct {
+	AccountIDs []string
+	DeviceIDs  []string
+	Categories []base.EventCategory
+	EventNames []string
+	Predicate  func(event *base.BaseEvent) bool
+}
+
+func (f Filter) Matches(event *base.BaseEvent) bool {
+	return f.matcher()(event)
+}
+
+func (f Filter) matcher() func(event *base.BaseEvent) bool {
+	accounts := set(f.AccountIDs)
+	devices := set(f.DeviceIDs)
+	names := set(f.EventNames)
+	categories := make(map[base.EventCategory]bool, len(f.Categories))
+	for _, category := range f.Categories {
+		categories[category] = true
+	}
+	predicate := f.Predicate
+
+	return func(event *base.BaseEvent) bool {
+		switch {
+		case len(accounts) > 0 && !accounts[event.GetAccountID()]:
+			return false
+		case len(devices) > 0 && !devices[event.GetDeviceID()]:
+			return false
+		case len(categories) > 0 && !categories[event.GetCategory()]:
+			return false
+		case len(names) > 0 && !names[event.GetEventName()]:
+			return false
+		case predicate != nil && !predicate(event):
+			return false
+		}
+		return true
+	}
+}
+
+// AnyOf returns a filter matching events that match at least one of
+// filters. With no filters it matches everything.
+func AnyOf(filters ...Filter) Filter {
+	if len(filters) == 1 {
+		return filters[0]
+	}
+	matchers := make([]func(event *base.BaseEvent) bool, len(filters))
+	for i, filter := range filters {
+		matchers[i] = filter.matcher()
+	}
+	return Filter{Predicate: func(event *base.BaseEvent) bool {
+		for _, matches := range matchers {
+			if matches(event) {
+				return true
+			}
+		}
+		return len(matchers) == 0
+	}}
+}
+
+func Where(filter Filter) Middleware {
+	matches := filter.matcher()
+	return func(next Handler) Handler {
+		return func(ctx context.Context, event *base.BaseEvent) error {
+			if !matches(event) {
+				return nil
+			}
+			return next(ctx, event)
+		}
+	}
+}
+
+type route struct {
+	id      int
+	matches func(event *base.BaseEvent) bool
+	handler Handler
+}
+
+// Router delivers each event to every handler whose filter matches, in
+// registration order.
+type Router struct {
+	mu     sync.RWMutex
+	routes []route
+	nextID int
+}
+
+func NewRouter() *Router {
+	return &Router{}
+}
+
+func (r *Router) Handle(filter Filter, handler Handler, middlewares ...Middleware) (remove func()) {
+	r.mu.Lock()
+	id := r.nextID
+	r.nextID++
+	r.routes = append(r.routes, route{
+		id:      id,
+		matches: filter.matcher(),
+		handler: Chain(handler, middlewares...),
+	})
+	r.mu.Unlock()
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for i, rt := range r.routes {
+			if rt.id == id {
+				r.routes = append(r.routes[:i:i], r.routes[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (r *Router) Handler() Handler {
+	return func(ctx context.Context, event *base.BaseEvent) error {
+		r.mu.RLock()
+		routes := r.routes
+		r.mu.RUnlock()
+
+		var errs []error
+		for _, rt := range routes {
+			if rt.matches(event) {
+				errs = append(errs, rt.handler(ctx, event))
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
+
+func set(values []string) map[string]bool {
+	s := make(map[string]bool, len(values))
+	for _, value := range values {
+		s[value] = true
+	}
+	return s
+}
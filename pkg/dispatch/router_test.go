@@ -0,0 +1,144 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go-eventlib/pkg/types/base"
+)
+
+func dmsEvent(account, device, name string) *base.BaseEvent {
+	return &base.BaseEvent{
+		ID:       account + "-" + device + "-" + name,
+		Category: "EVENT_CATEGORY_DMS",
+		Attributes: base.Attributes{
+			Device: &base.Device{ID: device, AccountID: account},
+			Data: &base.Data{TripEvent: map[string]interface{}{
+				"dms": map[string]interface{}{"event_name": name},
+			}},
+		},
+	}
+}
+
+func TestFilter_Matches(t *testing.T) {
+	event := dmsEvent("account-a", "device-1", "DROWSINESS")
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"vazio", Filter{}, true},
+		{"conta", Filter{AccountIDs: []string{"account-b", "account-a"}}, true},
+		{"outra conta", Filter{AccountIDs: []string{"account-b"}}, false},
+		{"dispositivo", Filter{DeviceIDs: []string{"device-2"}}, false},
+		{"categoria", Filter{Categories: []base.EventCategory{"EVENT_CATEGORY_DMS"}}, true},
+		{"nome", Filter{EventNames: []string{"DROWSINESS", "YAWNING"}}, true},
+		{"todos os campos", Filter{
+			AccountIDs: []string{"account-a"},
+			Categories: []base.EventCategory{"EVENT_CATEGORY_VISION"},
+		}, false},
+		{"predicado", Filter{Predicate: func(e *base.BaseEvent) bool { return e.GetDeviceID() == "device-1" }}, true},
+		{"predicado falso", Filter{
+			AccountIDs: []string{"account-a"},
+			Predicate:  func(e *base.BaseEvent) bool { return false },
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(event); got != tt.want {
+				t.Errorf("Matches() = %v, esperava %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnyOf(t *testing.T) {
+	event := dmsEvent("account-a", "device-1", "DROWSINESS")
+
+	if !AnyOf().Matches(event) {
+		t.Error("AnyOf() sem filtros deveria casar tudo")
+	}
+	if !AnyOf(Filter{AccountIDs: []string{"account-b"}}, Filter{DeviceIDs: []string{"device-1"}}).Matches(event) {
+		t.Error("AnyOf() deveria casar pelo dispositivo")
+	}
+	if AnyOf(Filter{AccountIDs: []string{"account-b"}}, Filter{EventNames: []string{"YAWNING"}}).Matches(event) {
+		t.Error("AnyOf() não deveria casar nenhum filtro")
+	}
+}
+
+func TestRouter_RoutesByAccount(t *testing.T) {
+	var calls []string
+	record := func(name string) Handler {
+		return func(ctx context.Context, event *base.BaseEvent) error {
+			calls = append(calls, name+":"+event.GetAccountID())
+			return nil
+		}
+	}
+
+	router := NewRouter()
+	drowsiness := []string{"DROWSINESS"}
+	router.Handle(Filter{AccountIDs: []string{"account-a"}, EventNames: drowsiness}, record("a"))
+	removeB := router.Handle(Filter{AccountIDs: []string{"account-b"}, EventNames: drowsiness}, record("b"))
+	router.Handle(Filter{}, record("all"))
+
+	handler := router.Handler()
+	handler(context.Background(), dmsEvent("account-a", "device-1", "DROWSINESS"))
+	handler(context.Background(), dmsEvent("account-b", "device-2", "DROWSINESS"))
+	handler(context.Background(), dmsEvent("account-b", "device-2", "YAWNING"))
+
+	want := "a:account-a,all:account-a,b:account-b,all:account-b,all:account-b"
+	if got := strings.Join(calls, ","); got != want {
+		t.Errorf("calls = %s, esperava %s", got, want)
+	}
+
+	calls = nil
+	removeB()
+	removeB()
+	handler(context.Background(), dmsEvent("account-b", "device-2", "DROWSINESS"))
+	if got := strings.Join(calls, ","); got != "all:account-b" {
+		t.Errorf("calls após remover = %s, esperava all:account-b", got)
+	}
+}
+
+func TestRouter_JoinsErrorsAndAppliesMiddlewares(t *testing.T) {
+	errA := errors.New("falha a")
+	applied := 0
+	counter := func(next Handler) Handler {
+		return func(ctx context.Context, event *base.BaseEvent) error {
+			applied++
+			return next(ctx, event)
+		}
+	}
+
+	router := NewRouter()
+	router.Handle(Filter{}, func(ctx context.Context, event *base.BaseEvent) error { return errA }, counter)
+	router.Handle(Filter{DeviceIDs: []string{"device-9"}}, func(ctx context.Context, event *base.BaseEvent) error {
+		t.Error("handler de outro dispositivo chamado")
+		return nil
+	}, counter)
+
+	if err := router.Handler()(context.Background(), dmsEvent("account-a", "device-1", "DROWSINESS")); !errors.Is(err, errA) {
+		t.Errorf("Handler() = %v, esperava %v", err, errA)
+	}
+	if applied != 1 {
+		t.Errorf("middleware aplicado %d vezes, esperava 1", applied)
+	}
+}
+
+func TestWhere(t *testing.T) {
+	called := 0
+	handler := Chain(func(ctx context.Context, event *base.BaseEvent) error {
+		called++
+		return nil
+	}, Where(Filter{AccountIDs: []string{"account-a"}}))
+
+	handler(context.Background(), dmsEvent("account-a", "device-1", "DROWSINESS"))
+	handler(context.Background(), dmsEvent("account-b", "device-1", "DROWSINESS"))
+
+	if called != 1 {
+		t.Errorf("handler chamado %d vezes, esperava 1", called)
+	}
+}
@@ -0,0 +1,179 @@
+package eventio
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+	"os"
+	"path"
+	"strings"
+
+	"go-eventlib/pkg/types/base"
+)
+
+func Read(r io.Reader) iter.Seq2[*base.BaseEvent, error] {
+	return func(yield func(*base.BaseEvent, error) bool) {
+		decode(r, func(event *base.BaseEvent, err error) bool {
+			if err != nil {
+				err = fmt.Errorf("eventio: %w", err)
+			}
+			return yield(event, err)
+		})
+	}
+}
+
+func ReadFile(name string) iter.Seq2[*base.BaseEvent, error] {
+	return func(yield func(*base.BaseEvent, error) bool) {
+		f, err := os.Open(name)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer f.Close()
+
+		decode(f, withPath(name, yield))
+	}
+}
+
+func ReadDir(fsys fs.FS, pattern string) iter.Seq2[*base.BaseEvent, error] {
+	return func(yield func(*base.BaseEvent, error) bool) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			yield(nil, err)
+			return
+		}
+		segments := splitPattern(pattern)
+
+		err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !match(segments, strings.Split(name, "/")) {
+				return nil
+			}
+
+			f, err := fsys.Open(name)
+			if err != nil {
+				if !yield(nil, err) {
+					return fs.SkipAll
+				}
+				return nil
+			}
+			defer f.Close()
+
+			if !decode(f, withPath(name, yield)) {
+				return fs.SkipAll
+			}
+			return nil
+		})
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+func decode(r io.Reader, yield func(*base.BaseEvent, error) bool) bool {
+	br := bufio.NewReader(r)
+
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return yield(nil, err)
+		}
+		defer zr.Close()
+		br = bufio.NewReader(zr)
+	}
+
+	first, err := peekNonSpace(br)
+	if err == io.EOF {
+		return true
+	}
+	if err != nil {
+		return yield(nil, err)
+	}
+
+	dec := json.NewDecoder(br)
+	if first == '[' {
+		if _, err := dec.Token(); err != nil {
+			return yield(nil, err)
+		}
+	}
+
+	for index := 0; dec.More(); index++ {
+		var event base.BaseEvent
+		if err := dec.Decode(&event); err != nil {
+			return yield(nil, fmt.Errorf("event %d: %w", index, err))
+		}
+		if !yield(&event, nil) {
+			return false
+		}
+	}
+
+	if first == '[' {
+		if _, err := dec.Token(); err != nil {
+			return yield(nil, err)
+		}
+	}
+	return true
+}
+
+func withPath(name string, yield func(*base.BaseEvent, error) bool) func(*base.BaseEvent, error) bool {
+	return func(event *base.BaseEvent, err error) bool {
+		if err != nil {
+			err = fmt.Errorf("eventio: %s: %w", name, err)
+		}
+		return yield(event, err)
+	}
+}
+
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b, br.UnreadByte()
+	}
+}
+
+func splitPattern(pattern string) []string {
+	var segments []string
+	for _, segment := range strings.Split(pattern, "/") {
+		if segment != "**" && strings.HasPrefix(segment, "**") {
+			segments = append(segments, "**", segment[1:])
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	return segments
+}
+
+func match(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if match(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(name) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], name[0]); !ok {
+		return false
+	}
+	return match(pattern[1:], name[1:])
+}
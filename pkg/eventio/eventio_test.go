@@ -0,0 +1,143 @@
+package eventio
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"go-eventlib/pkg/types/base"
+)
+
+func collect(t *testing.T, seq func(yield func(*base.BaseEvent, error) bool)) ([]string, []error) {
+	t.Helper()
+
+	var (
+		ids  []string
+		errs []error
+	)
+	for event, err := range seq {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, event.GetID())
+	}
+	return ids, errs
+}
+
+func gzipped(t *testing.T, data string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(data))
+	if err := zw.Close(); err != nil {
+		t.Fatalf("erro ao comprimir: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestRead_Formats(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  string
+	}{
+		{"objeto", []byte(`{"id":"a"}`), "a"},
+		{"objeto formatado", []byte("{\n  \"id\": \"a\"\n}\n"), "a"},
+		{"array", []byte(`[{"id":"a"}, {"id":"b"}]`), "a,b"},
+		{"array vazio", []byte(`[]`), ""},
+		{"ndjson", []byte("{\"id\":\"a\"}\n\n{\"id\":\"b\"}\n"), "a,b"},
+		{"vazio", []byte("  \n"), ""},
+		{"gzip", gzipped(t, "{\"id\":\"a\"}\n{\"id\":\"b\"}\n"), "a,b"},
+		{"gzip array", gzipped(t, `[{"id":"a"}]`), "a"},
+	}
+
+	for _, tt := range tests {
+		ids, errs := collect(t, Read(bytes.NewReader(tt.input)))
+		if len(errs) > 0 {
+			t.Errorf("%s: erro inesperado: %v", tt.name, errs)
+		}
+		if got := strings.Join(ids, ","); got != tt.want {
+			t.Errorf("%s: ids = %q, esperava %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRead_InvalidJSON(t *testing.T) {
+	ids, errs := collect(t, Read(strings.NewReader("{\"id\":\"a\"}\n{\"id\":")))
+	if len(ids) != 1 || len(errs) != 1 {
+		t.Errorf("ids = %v, erros = %v, esperava 1 evento e 1 erro", ids, errs)
+	}
+}
+
+func TestRead_StopsOnBreak(t *testing.T) {
+	count := 0
+	for range Read(strings.NewReader(`[{"id":"a"},{"id":"b"},{"id":"c"}]`)) {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("iterações = %d, esperava 2", count)
+	}
+}
+
+func TestReadFile_Missing(t *testing.T) {
+	_, errs := collect(t, ReadFile(filepath.Join(t.TempDir(), "missing.json")))
+	if len(errs) != 1 || !os.IsNotExist(errs[0]) {
+		t.Errorf("erros = %v, esperava os.ErrNotExist", errs)
+	}
+}
+
+func TestReadDir_Fixtures(t *testing.T) {
+	files, err := filepath.Glob("../../test/events/*/*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("nenhum fixture encontrado: %v", err)
+	}
+
+	ids, errs := collect(t, ReadDir(os.DirFS("../../test"), "events/**.json"))
+	if len(errs) > 0 {
+		t.Errorf("erros inesperados: %v", errs)
+	}
+	if len(ids) != len(files) {
+		t.Errorf("ReadDir() leu %d eventos, esperava %d", len(ids), len(files))
+	}
+}
+
+func TestReadDir_Patterns(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.json":                         {Data: []byte(`{"id":"a"}`)},
+		"2025-03-01/acc/events.jsonl.gz": {Data: gzipped(t, "{\"id\":\"b\"}\n{\"id\":\"c\"}\n")},
+		"2025-03-01/acc/notes.txt":       {Data: []byte("not json")},
+		"nested/deep/d.json":             {Data: []byte(`[{"id":"d"}]`)},
+		"nested/bad.json":                {Data: []byte(`{"id":`)},
+	}
+
+	tests := []struct {
+		pattern string
+		want    string
+		errs    int
+	}{
+		{"*.json", "a", 0},
+		{"**/*.jsonl.gz", "b,c", 0},
+		{"**.json", "a,d", 1},
+		{"nested/**/*.json", "d", 1},
+		{"nested/*/*.json", "d", 0},
+	}
+
+	for _, tt := range tests {
+		ids, errs := collect(t, ReadDir(fsys, tt.pattern))
+		if got := strings.Join(ids, ","); got != tt.want {
+			t.Errorf("ReadDir(%q) = %q, esperava %q", tt.pattern, got, tt.want)
+		}
+		if len(errs) != tt.errs {
+			t.Errorf("ReadDir(%q) erros = %v, esperava %d", tt.pattern, errs, tt.errs)
+		}
+	}
+}
@@ -0,0 +1,348 @@
+package fatigue
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/dms"
+)
+
+const EventName = "FATIGUE_LEVEL_CHANGED"
+
+type Level int
+
+const (
+	LevelAlert Level = iota
+	LevelDrowsy
+	LevelCritical
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelAlert:
+		return "alert"
+	case LevelDrowsy:
+		return "drowsy"
+	case LevelCritical:
+		return "critical"
+	default:
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+}
+
+type Threshold struct {
+	PERCLOS        float64
+	Events         int
+	BlinkRateSlope float64
+}
+
+type Config struct {
+	Window     time.Duration
+	MinSamples int
+	Events     []string
+	Drowsy     Threshold
+	Critical   Threshold
+	OnChange   func(change *Change)
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Window:     10 * time.Minute,
+		MinSamples: 2,
+		Events:     []string{"DROWSINESS", "EYE_CLOSURE", "YAWNING"},
+		Drowsy:     Threshold{PERCLOS: 0.15, Events: 3, BlinkRateSlope: -1},
+		Critical:   Threshold{PERCLOS: 0.30, Events: 6},
+	}
+}
+
+type Stats struct {
+	PERCLOS        float64
+	PERCLOSSamples int
+	BlinkRate      float64
+	BlinkRateSlope float64
+	BlinkSamples   int
+	Events         int
+}
+
+type Change struct {
+	DeviceID  string
+	AccountID string
+	TripID    string
+	From      Level
+	To        Level
+	At        time.Time
+	Stats     Stats
+}
+
+func (c *Change) Event() *base.BaseEvent {
+	return &base.BaseEvent{
+		ID:        fmt.Sprintf("fatigue-%s-%d", c.DeviceID, c.At.UnixNano()),
+		Status:    "STATUS_RECEIVED",
+		CreatedAt: c.At,
+		Type:      "EVENT_TYPE_DERIVED",
+		Category:  "EVENT_CATEGORY_DMS",
+		Sub:       "EVENT_SUB_DMS_ADVANCED",
+		Attributes: base.Attributes{
+			Device: &base.Device{ID: c.DeviceID, AccountID: c.AccountID},
+			Data: &base.Data{
+				GroupName: "TRIP_EVENT",
+				TripEvent: map[string]interface{}{
+					"trip_id":          c.TripID,
+					"event_group_name": "DMS",
+					"dms": map[string]interface{}{
+						"event_name": EventName,
+						"fatigue_level_changed": map[string]interface{}{
+							"name": EventName,
+							"from": c.From.String(),
+							"to":   c.To.String(),
+							"attributes": map[string]interface{}{
+								"perclos":              fmt.Sprintf("%.3f", c.Stats.PERCLOS),
+								"blinks_per_min":       fmt.Sprintf("%.1f", c.Stats.BlinkRate),
+								"blinks_per_min_trend": fmt.Sprintf("%.3f", c.Stats.BlinkRateSlope),
+								"fatigue_events":       fmt.Sprint(c.Stats.Events),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+type sample struct {
+	at        time.Time
+	fatigue   bool
+	perclos   float64
+	hasPerc   bool
+	blinkRate float64
+	hasBlink  bool
+}
+
+type device struct {
+	accountID string
+	tripID    string
+	level     Level
+	samples   []sample
+}
+
+type Monitor struct {
+	cfg    Config
+	events map[string]bool
+
+	mu      sync.Mutex
+	devices map[string]*device
+}
+
+func NewMonitor(cfg Config) *Monitor {
+	defaults := DefaultConfig()
+	if cfg.Window <= 0 {
+		cfg.Window = defaults.Window
+	}
+	if cfg.MinSamples <= 0 {
+		cfg.MinSamples = defaults.MinSamples
+	}
+	if cfg.Events == nil {
+		cfg.Events = defaults.Events
+	}
+	if cfg.Drowsy == (Threshold{}) {
+		cfg.Drowsy = defaults.Drowsy
+	}
+	if cfg.Critical == (Threshold{}) {
+		cfg.Critical = defaults.Critical
+	}
+
+	events := make(map[string]bool, len(cfg.Events))
+	for _, name := range cfg.Events {
+		events[name] = true
+	}
+
+	return &Monitor{
+		cfg:     cfg,
+		events:  events,
+		devices: make(map[string]*device),
+	}
+}
+
+func (m *Monitor) Add(event *dms.Event) *Change {
+	s := sample{at: event.GetCreatedAt(), fatigue: m.events[event.GetEventName()]}
+	s.perclos, s.hasPerc = event.GetFloatAttribute("perclos")
+	s.blinkRate, s.hasBlink = event.GetFloatAttribute("blinks_per_min")
+	if !s.fatigue && !s.hasPerc && !s.hasBlink {
+		return nil
+	}
+
+	deviceID := event.GetDeviceID()
+
+	m.mu.Lock()
+	d, ok := m.devices[deviceID]
+	if !ok {
+		d = &device{}
+		m.devices[deviceID] = d
+	}
+	d.accountID = event.GetAccountID()
+	if tripID := event.GetTripID(); tripID != "" {
+		d.tripID = tripID
+	}
+
+	i := sort.Search(len(d.samples), func(i int) bool { return d.samples[i].at.After(s.at) })
+	d.samples = append(d.samples, sample{})
+	copy(d.samples[i+1:], d.samples[i:])
+	d.samples[i] = s
+
+	change := m.evaluateLocked(deviceID, d, d.samples[len(d.samples)-1].at)
+	m.mu.Unlock()
+
+	m.emit(change)
+	return change
+}
+
+func (m *Monitor) Expire(now time.Time) []*Change {
+	m.mu.Lock()
+	var changes []*Change
+	for deviceID, d := range m.devices {
+		if change := m.evaluateLocked(deviceID, d, now); change != nil {
+			changes = append(changes, change)
+		}
+		if len(d.samples) == 0 {
+			delete(m.devices, deviceID)
+		}
+	}
+	m.mu.Unlock()
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].DeviceID < changes[j].DeviceID })
+	for _, change := range changes {
+		m.emit(change)
+	}
+	return changes
+}
+
+func (m *Monitor) Level(deviceID string) (Level, Stats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	d, ok := m.devices[deviceID]
+	if !ok {
+		return LevelAlert, Stats{}
+	}
+	return d.level, m.stats(d.samples)
+}
+
+func (m *Monitor) Handler() dispatch.Handler {
+	return func(ctx context.Context, event *base.BaseEvent) error {
+		if event.GetCategory() == "EVENT_CATEGORY_DMS" {
+			m.Add(dms.New(event))
+		}
+		return nil
+	}
+}
+
+func (m *Monitor) evaluateLocked(deviceID string, d *device, now time.Time) *Change {
+	cutoff := now.Add(-m.cfg.Window)
+	i := sort.Search(len(d.samples), func(i int) bool { return d.samples[i].at.After(cutoff) })
+	d.samples = d.samples[i:]
+
+	stats := m.stats(d.samples)
+	level := m.level(stats)
+	if level == d.level {
+		return nil
+	}
+
+	change := &Change{
+		DeviceID:  deviceID,
+		AccountID: d.accountID,
+		TripID:    d.tripID,
+		From:      d.level,
+		To:        level,
+		At:        now,
+		Stats:     stats,
+	}
+	d.level = level
+	return change
+}
+
+func (m *Monitor) stats(samples []sample) Stats {
+	var (
+		stats   Stats
+		perclos float64
+		xs, ys  []float64
+	)
+
+	for _, s := range samples {
+		if s.fatigue {
+			stats.Events++
+		}
+		if s.hasPerc {
+			perclos += s.perclos
+			stats.PERCLOSSamples++
+		}
+		if s.hasBlink {
+			xs = append(xs, s.at.Sub(samples[0].at).Minutes())
+			ys = append(ys, s.blinkRate)
+		}
+	}
+
+	if stats.PERCLOSSamples > 0 {
+		stats.PERCLOS = perclos / float64(stats.PERCLOSSamples)
+	}
+	stats.BlinkSamples = len(ys)
+	if len(ys) > 0 {
+		stats.BlinkRate = ys[len(ys)-1]
+		stats.BlinkRateSlope = slope(xs, ys)
+	}
+	return stats
+}
+
+func (m *Monitor) level(stats Stats) Level {
+	switch {
+	case m.exceeds(stats, m.cfg.Critical):
+		return LevelCritical
+	case m.exceeds(stats, m.cfg.Drowsy):
+		return LevelDrowsy
+	default:
+		return LevelAlert
+	}
+}
+
+func (m *Monitor) exceeds(stats Stats, t Threshold) bool {
+	switch {
+	case t.PERCLOS > 0 && stats.PERCLOSSamples >= m.cfg.MinSamples && stats.PERCLOS >= t.PERCLOS:
+		return true
+	case t.Events > 0 && stats.Events >= t.Events:
+		return true
+	case t.BlinkRateSlope < 0 && stats.BlinkSamples >= m.cfg.MinSamples && stats.BlinkRateSlope <= t.BlinkRateSlope:
+		return true
+	}
+	return false
+}
+
+func (m *Monitor) emit(change *Change) {
+	if change != nil && m.cfg.OnChange != nil {
+		m.cfg.OnChange(change)
+	}
+}
+
+func slope(xs, ys []float64) float64 {
+	n := float64(len(xs))
+	if n < 2 {
+		return 0
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+		sumXY += xs[i] * ys[i]
+		sumXX += xs[i] * xs[i]
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denominator
+}
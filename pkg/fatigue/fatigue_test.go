@@ -0,0 +1,135 @@
+package fatigue
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/dms"
+)
+
+var start = time.Date(2025, 3, 1, 2, 0, 0, 0, time.UTC)
+
+func detection(device, name string, offset time.Duration, attributes map[string]interface{}) *dms.Event {
+	return dms.New(&base.BaseEvent{
+		ID:        fmt.Sprintf("%s-%s-%d", device, name, offset),
+		CreatedAt: start.Add(offset),
+		Category:  "EVENT_CATEGORY_DMS",
+		Attributes: base.Attributes{
+			Device: &base.Device{ID: device, AccountID: "account-1"},
+			Data: &base.Data{TripEvent: map[string]interface{}{
+				"trip_id": "trip-1",
+				"dms": map[string]interface{}{
+					"event_name": name,
+					"detection":  map[string]interface{}{"attributes": attributes},
+				},
+			}},
+		},
+	})
+}
+
+func drowsiness(device string, offset time.Duration, perclos, blinks string) *dms.Event {
+	return detection(device, "DROWSINESS", offset, map[string]interface{}{"perclos": perclos, "blinks_per_min": blinks})
+}
+
+func TestMonitor_EscalatesOnRollingPERCLOS(t *testing.T) {
+	var changes []*Change
+	m := NewMonitor(Config{
+		Drowsy:   Threshold{PERCLOS: 0.15},
+		Critical: Threshold{PERCLOS: 0.30},
+		Events:   []string{},
+		OnChange: func(change *Change) { changes = append(changes, change) },
+	})
+
+	if change := m.Add(drowsiness("device-1", 0, "0.40", "12")); change != nil {
+		t.Fatalf("uma única detecção escalou para %s", change.To)
+	}
+
+	m.Add(drowsiness("device-1", time.Minute, "0.10", "12"))
+	if len(changes) != 1 || changes[0].To != LevelDrowsy {
+		t.Fatalf("changes = %+v, esperava alert → drowsy", changes)
+	}
+
+	m.Add(drowsiness("device-1", 2*time.Minute, "0.45", "12"))
+	if len(changes) != 2 || changes[1].From != LevelDrowsy || changes[1].To != LevelCritical {
+		t.Fatalf("changes = %+v, esperava drowsy → critical", changes)
+	}
+
+	if level, stats := m.Level("device-1"); level != LevelCritical || stats.PERCLOSSamples != 3 {
+		t.Errorf("Level() = %s, %+v", level, stats)
+	}
+
+	expired := m.Expire(start.Add(time.Hour))
+	if len(expired) != 1 || expired[0].To != LevelAlert {
+		t.Errorf("Expire() = %+v, esperava critical → alert", expired)
+	}
+	if level, _ := m.Level("device-1"); level != LevelAlert {
+		t.Errorf("Level() após Expire = %s, esperava alert", level)
+	}
+}
+
+func TestMonitor_EventFrequency(t *testing.T) {
+	m := NewMonitor(Config{Drowsy: Threshold{Events: 3}, Critical: Threshold{Events: 5}, Window: 5 * time.Minute})
+
+	var last *Change
+	for i := 0; i < 3; i++ {
+		if change := m.Add(detection("device-1", "YAWNING", time.Duration(i)*time.Minute, nil)); change != nil {
+			last = change
+		}
+	}
+	if last == nil || last.To != LevelDrowsy || last.Stats.Events != 3 {
+		t.Fatalf("último change = %+v, esperava drowsy com 3 eventos", last)
+	}
+
+	if change := m.Add(detection("device-1", "ON_PHONE", 3*time.Minute, nil)); change != nil {
+		t.Errorf("evento fora da lista alterou o nível: %+v", change)
+	}
+
+	change := m.Add(detection("device-1", "YAWNING", 10*time.Minute, nil))
+	if change == nil || change.To != LevelAlert {
+		t.Errorf("change = %+v, esperava retorno a alert após a janela", change)
+	}
+}
+
+func TestMonitor_BlinkRateTrend(t *testing.T) {
+	m := NewMonitor(Config{Drowsy: Threshold{BlinkRateSlope: -1}, Critical: Threshold{PERCLOS: 0.9}, MinSamples: 3})
+
+	var change *Change
+	for i, blinks := range []string{"20", "17", "14"} {
+		change = m.Add(drowsiness("device-1", time.Duration(i)*time.Minute, "0.05", blinks))
+	}
+
+	if change == nil || change.To != LevelDrowsy {
+		t.Fatalf("change = %+v, esperava drowsy pela queda na taxa de piscadas", change)
+	}
+	if change.Stats.BlinkRateSlope != -3 || change.Stats.BlinkRate != 14 {
+		t.Errorf("Stats = %+v, esperava inclinação -3 e taxa 14", change.Stats)
+	}
+}
+
+func TestMonitor_DevicesAreIndependent(t *testing.T) {
+	m := NewMonitor(Config{Drowsy: Threshold{PERCLOS: 0.15}, Critical: Threshold{PERCLOS: 0.9}})
+
+	m.Add(drowsiness("device-1", 0, "0.30", "10"))
+	m.Add(drowsiness("device-2", time.Minute, "0.30", "10"))
+
+	if level, _ := m.Level("device-1"); level != LevelAlert {
+		t.Errorf("device-1 = %s, esperava alert com uma única amostra", level)
+	}
+}
+
+func TestChange_Event(t *testing.T) {
+	change := &Change{DeviceID: "device-1", AccountID: "account-1", TripID: "trip-1", From: LevelDrowsy, To: LevelCritical, At: start, Stats: Stats{PERCLOS: 0.35}}
+	event := dms.New(change.Event())
+
+	if event.GetEventName() != EventName || event.GetTripID() != "trip-1" || event.GetDeviceID() != "device-1" {
+		t.Errorf("Event() = %+v", event.BaseEvent)
+	}
+	if got, _ := event.GetAttribute("perclos"); got != "0.350" {
+		t.Errorf("perclos = %s, esperava 0.350", got)
+	}
+	if got := event.GetDetection()["to"]; got != "critical" {
+		t.Errorf("to = %v, esperava critical", got)
+	}
+}
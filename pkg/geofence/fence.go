@@ -0,0 +1,220 @@
+package geofence
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"go-eventlib/pkg/stats"
+	"go-eventlib/pkg/types/common"
+)
+
+const metersPerDegree = 111320.0
+
+var ErrInvalidGeoJSON = errors.New("geofence: invalid GeoJSON")
+
+type Point struct {
+	Longitude float64
+	Latitude  float64
+}
+
+type Fence struct {
+	ID         string
+	Name       string
+	Properties map[string]interface{}
+	Polygons   [][][]Point
+	Center     *Point
+	Radius     float64
+
+	bounds rect
+}
+
+func NewPolygon(id string, rings ...[]Point) *Fence {
+	f := &Fence{ID: id, Polygons: [][][]Point{rings}}
+	f.computeBounds()
+	return f
+}
+
+func NewCircle(id string, center Point, radius float64) *Fence {
+	f := &Fence{ID: id, Center: &center, Radius: radius}
+	f.computeBounds()
+	return f
+}
+
+func (f *Fence) Contains(coords common.Coordinates) bool {
+	if !f.bounds.contains(coords.Longitude, coords.Latitude) {
+		return false
+	}
+
+	if f.Center != nil {
+		center := common.Coordinates{Latitude: f.Center.Latitude, Longitude: f.Center.Longitude}
+		return stats.Haversine(center, coords) <= f.Radius
+	}
+
+	p := Point{Longitude: coords.Longitude, Latitude: coords.Latitude}
+	for _, polygon := range f.Polygons {
+		if len(polygon) == 0 || !inRing(p, polygon[0]) {
+			continue
+		}
+		inHole := false
+		for _, hole := range polygon[1:] {
+			if inRing(p, hole) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Fence) computeBounds() {
+	if f.Center != nil {
+		dLat := f.Radius / metersPerDegree
+		dLon := dLat / math.Max(math.Cos(f.Center.Latitude*math.Pi/180), 1e-6)
+		f.bounds = rect{
+			minX: f.Center.Longitude - dLon,
+			minY: f.Center.Latitude - dLat,
+			maxX: f.Center.Longitude + dLon,
+			maxY: f.Center.Latitude + dLat,
+		}
+		return
+	}
+
+	f.bounds = rect{minX: math.Inf(1), minY: math.Inf(1), maxX: math.Inf(-1), maxY: math.Inf(-1)}
+	for _, polygon := range f.Polygons {
+		if len(polygon) == 0 {
+			continue
+		}
+		for _, p := range polygon[0] {
+			f.bounds = f.bounds.union(rect{minX: p.Longitude, minY: p.Latitude, maxX: p.Longitude, maxY: p.Latitude})
+		}
+	}
+}
+
+func inRing(p Point, ring []Point) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Latitude > p.Latitude) != (b.Latitude > p.Latitude) &&
+			p.Longitude < (b.Longitude-a.Longitude)*(p.Latitude-a.Latitude)/(b.Latitude-a.Latitude)+a.Longitude {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+type geoJSON struct {
+	Type       string                 `json:"type"`
+	ID         interface{}            `json:"id"`
+	Properties map[string]interface{} `json:"properties"`
+	Geometry   *geometry              `json:"geometry"`
+	Features   []geoJSON              `json:"features"`
+}
+
+type geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+func LoadGeoJSON(r io.Reader) ([]*Fence, error) {
+	var doc geoJSON
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGeoJSON, err)
+	}
+
+	features := []geoJSON{doc}
+	if doc.Type == "FeatureCollection" {
+		features = doc.Features
+	}
+
+	fences := make([]*Fence, 0, len(features))
+	for i, feature := range features {
+		f, err := parseFeature(feature)
+		if err != nil {
+			return nil, fmt.Errorf("feature %d: %w", i, err)
+		}
+		fences = append(fences, f)
+	}
+	return fences, nil
+}
+
+func parseFeature(feature geoJSON) (*Fence, error) {
+	if feature.Type != "Feature" || feature.Geometry == nil {
+		return nil, fmt.Errorf("%w: expected Feature with geometry, got %q", ErrInvalidGeoJSON, feature.Type)
+	}
+
+	f := &Fence{
+		ID:         featureID(feature),
+		Properties: feature.Properties,
+	}
+	if name, ok := feature.Properties["name"].(string); ok {
+		f.Name = name
+	}
+
+	switch feature.Geometry.Type {
+	case "Polygon":
+		var rings [][][2]float64
+		if err := json.Unmarshal(feature.Geometry.Coordinates, &rings); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidGeoJSON, err)
+		}
+		f.Polygons = [][][]Point{toRings(rings)}
+	case "MultiPolygon":
+		var polygons [][][][2]float64
+		if err := json.Unmarshal(feature.Geometry.Coordinates, &polygons); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidGeoJSON, err)
+		}
+		for _, rings := range polygons {
+			f.Polygons = append(f.Polygons, toRings(rings))
+		}
+	case "Point":
+		var position [2]float64
+		if err := json.Unmarshal(feature.Geometry.Coordinates, &position); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidGeoJSON, err)
+		}
+		radius, ok := feature.Properties["radius"].(float64)
+		if !ok || radius <= 0 {
+			return nil, fmt.Errorf("%w: Point fence %q needs a positive radius property", ErrInvalidGeoJSON, f.ID)
+		}
+		f.Center = &Point{Longitude: position[0], Latitude: position[1]}
+		f.Radius = radius
+	default:
+		return nil, fmt.Errorf("%w: unsupported geometry %q", ErrInvalidGeoJSON, feature.Geometry.Type)
+	}
+
+	if f.ID == "" {
+		return nil, fmt.Errorf("%w: feature without id", ErrInvalidGeoJSON)
+	}
+
+	f.computeBounds()
+	return f, nil
+}
+
+func featureID(feature geoJSON) string {
+	for _, id := range []interface{}{feature.ID, feature.Properties["id"], feature.Properties["name"]} {
+		switch v := id.(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case float64:
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
+
+func toRings(rings [][][2]float64) [][]Point {
+	out := make([][]Point, len(rings))
+	for i, ring := range rings {
+		out[i] = make([]Point, len(ring))
+		for j, position := range ring {
+			out[i][j] = Point{Longitude: position[0], Latitude: position[1]}
+		}
+	}
+	return out
+}
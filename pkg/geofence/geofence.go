@@ -0,0 +1,248 @@
+package geofence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/common"
+)
+
+const (
+	EventGeofenceEnter = "GEOFENCE_ENTER"
+	EventGeofenceExit  = "GEOFENCE_EXIT"
+	EventGeofenceDwell = "GEOFENCE_DWELL"
+)
+
+type Kind string
+
+const (
+	GeofenceEnter Kind = EventGeofenceEnter
+	GeofenceExit  Kind = EventGeofenceExit
+	Dwell         Kind = EventGeofenceDwell
+)
+
+type Transition struct {
+	Kind        Kind
+	Fence       *Fence
+	DeviceID    string
+	AccountID   string
+	TripID      string
+	At          time.Time
+	Coordinates common.Coordinates
+	Duration    time.Duration
+	SourceID    string
+}
+
+func (t *Transition) Event() *base.BaseEvent {
+	name := string(t.Kind)
+	detail := map[string]interface{}{
+		"name":       name,
+		"fence_id":   t.Fence.ID,
+		"fence_name": t.Fence.Name,
+		"source_id":  t.SourceID,
+		"location": map[string]interface{}{
+			"coordinates": map[string]interface{}{
+				"latitude":  t.Coordinates.Latitude,
+				"longitude": t.Coordinates.Longitude,
+				"speed":     t.Coordinates.Speed,
+			},
+		},
+	}
+	if t.Kind != GeofenceEnter {
+		detail["duration_seconds"] = t.Duration.Seconds()
+	}
+
+	return &base.BaseEvent{
+		ID:        fmt.Sprintf("geofence-%s-%s-%s-%d", t.DeviceID, t.Fence.ID, name, t.At.UnixNano()),
+		Status:    "STATUS_RECEIVED",
+		CreatedAt: t.At,
+		Type:      "EVENT_TYPE_DERIVED",
+		Category:  "EVENT_CATEGORY_GEOFENCE",
+		Attributes: base.Attributes{
+			Device: &base.Device{ID: t.DeviceID, AccountID: t.AccountID},
+			Data: &base.Data{
+				GroupName: "STANDALONE_EVENT",
+				StandaloneEvent: map[string]interface{}{
+					"event_group_name": "GEOFENCE",
+					"geofence": map[string]interface{}{
+						"event_name": name,
+						"trip_id":    t.TripID,
+						"transition": detail,
+					},
+				},
+			},
+		},
+	}
+}
+
+type Config struct {
+	DwellTime time.Duration
+	Handler   dispatch.Handler
+}
+
+type presence struct {
+	since   time.Time
+	dwelled bool
+}
+
+type device struct {
+	lastAt time.Time
+	inside map[string]*presence
+}
+
+type Monitor struct {
+	cfg Config
+
+	mu      sync.RWMutex
+	fences  map[string]*Fence
+	index   *rtree
+	devices map[string]*device
+}
+
+func NewMonitor(fences []*Fence, cfg Config) *Monitor {
+	m := &Monitor{
+		cfg:     cfg,
+		devices: make(map[string]*device),
+	}
+	m.SetFences(fences)
+	return m
+}
+
+func (m *Monitor) SetFences(fences []*Fence) {
+	byID := make(map[string]*Fence, len(fences))
+	for _, f := range fences {
+		byID[f.ID] = f
+	}
+	index := newRTree(fences)
+
+	m.mu.Lock()
+	m.fences = byID
+	m.index = index
+	m.mu.Unlock()
+}
+
+func (m *Monitor) Fences(coords common.Coordinates) []*Fence {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.lookupLocked(coords)
+}
+
+func (m *Monitor) Evaluate(event *base.BaseEvent) []*Transition {
+	coords := event.GetCoordinates()
+	if coords == nil || (coords.Latitude == 0 && coords.Longitude == 0) {
+		return nil
+	}
+
+	deviceID := event.GetDeviceID()
+	at := event.GetCreatedAt()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	d, ok := m.devices[deviceID]
+	if !ok {
+		d = &device{inside: make(map[string]*presence)}
+		m.devices[deviceID] = d
+	}
+	if at.Before(d.lastAt) {
+		return nil
+	}
+	d.lastAt = at
+
+	transition := func(kind Kind, f *Fence, duration time.Duration) *Transition {
+		return &Transition{
+			Kind:        kind,
+			Fence:       f,
+			DeviceID:    deviceID,
+			AccountID:   event.GetAccountID(),
+			TripID:      event.GetTripID(),
+			At:          at,
+			Coordinates: *coords,
+			Duration:    duration,
+			SourceID:    event.GetID(),
+		}
+	}
+
+	current := make(map[string]*Fence)
+	for _, f := range m.lookupLocked(*coords) {
+		current[f.ID] = f
+	}
+
+	var transitions []*Transition
+	for id, p := range d.inside {
+		if _, still := current[id]; still {
+			continue
+		}
+		f, ok := m.fences[id]
+		if !ok {
+			f = &Fence{ID: id}
+		}
+		transitions = append(transitions, transition(GeofenceExit, f, at.Sub(p.since)))
+		delete(d.inside, id)
+	}
+
+	for id, f := range current {
+		p, ok := d.inside[id]
+		if !ok {
+			d.inside[id] = &presence{since: at}
+			transitions = append(transitions, transition(GeofenceEnter, f, 0))
+			continue
+		}
+		if m.cfg.DwellTime > 0 && !p.dwelled && at.Sub(p.since) >= m.cfg.DwellTime {
+			p.dwelled = true
+			transitions = append(transitions, transition(Dwell, f, at.Sub(p.since)))
+		}
+	}
+
+	sort.SliceStable(transitions, func(i, j int) bool {
+		if transitions[i].Kind != transitions[j].Kind {
+			return kindOrder(transitions[i].Kind) < kindOrder(transitions[j].Kind)
+		}
+		return transitions[i].Fence.ID < transitions[j].Fence.ID
+	})
+	return transitions
+}
+
+func (m *Monitor) Handler() dispatch.Handler {
+	return func(ctx context.Context, event *base.BaseEvent) error {
+		if event.GetCategory() == "EVENT_CATEGORY_GEOFENCE" || m.cfg.Handler == nil {
+			m.Evaluate(event)
+			return nil
+		}
+
+		var errs []error
+		for _, t := range m.Evaluate(event) {
+			errs = append(errs, m.cfg.Handler(ctx, t.Event()))
+		}
+		return errors.Join(errs...)
+	}
+}
+
+func (m *Monitor) lookupLocked(coords common.Coordinates) []*Fence {
+	var fences []*Fence
+	m.index.search(coords.Longitude, coords.Latitude, func(f *Fence) {
+		if f.Contains(coords) {
+			fences = append(fences, f)
+		}
+	})
+	sort.Slice(fences, func(i, j int) bool { return fences[i].ID < fences[j].ID })
+	return fences
+}
+
+func kindOrder(kind Kind) int {
+	switch kind {
+	case GeofenceExit:
+		return 0
+	case GeofenceEnter:
+		return 1
+	default:
+		return 2
+	}
+}
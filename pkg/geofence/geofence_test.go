@@ -0,0 +1,233 @@
+package geofence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/common"
+)
+
+const fencesJSON = `{
+  "type": "FeatureCollection",
+  "features": [
+    {
+      "type": "Feature",
+      "id": "depot",
+      "properties": {"name": "Depot"},
+      "geometry": {
+        "type": "Polygon",
+        "coordinates": [
+          [[-46.70, -23.60], [-46.60, -23.60], [-46.60, -23.50], [-46.70, -23.50], [-46.70, -23.60]],
+          [[-46.66, -23.56], [-46.64, -23.56], [-46.64, -23.54], [-46.66, -23.54], [-46.66, -23.56]]
+        ]
+      }
+    },
+    {
+      "type": "Feature",
+      "properties": {"id": "client", "radius": 500},
+      "geometry": {"type": "Point", "coordinates": [-46.62, -23.52]}
+    },
+    {
+      "type": "Feature",
+      "properties": {"name": "islands"},
+      "geometry": {
+        "type": "MultiPolygon",
+        "coordinates": [
+          [[[-45.0, -23.0], [-44.9, -23.0], [-44.9, -22.9], [-45.0, -23.0]]],
+          [[[-44.0, -23.0], [-43.9, -23.0], [-43.9, -22.9], [-44.0, -23.0]]]
+        ]
+      }
+    }
+  ]
+}`
+
+var start = time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+
+func loadFences(t *testing.T) []*Fence {
+	t.Helper()
+
+	fences, err := LoadGeoJSON(strings.NewReader(fencesJSON))
+	if err != nil {
+		t.Fatalf("LoadGeoJSON() erro inesperado: %v", err)
+	}
+	return fences
+}
+
+func at(lat, lon float64) common.Coordinates {
+	return common.Coordinates{Latitude: lat, Longitude: lon}
+}
+
+func located(id, device string, offset time.Duration, lat, lon float64) *base.BaseEvent {
+	return &base.BaseEvent{
+		ID:        id,
+		CreatedAt: start.Add(offset),
+		Category:  "EVENT_CATEGORY_TELEMETRY",
+		Attributes: base.Attributes{
+			Device: &base.Device{ID: device, AccountID: "account-1"},
+			Data: &base.Data{StandaloneEvent: map[string]interface{}{
+				"telemetry": map[string]interface{}{
+					"event_name": "PERIODIC",
+					"periodic": map[string]interface{}{
+						"location": map[string]interface{}{
+							"coordinates": map[string]interface{}{"latitude": lat, "longitude": lon},
+						},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func kinds(transitions []*Transition) string {
+	var parts []string
+	for _, t := range transitions {
+		parts = append(parts, string(t.Kind)+":"+t.Fence.ID)
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestLoadGeoJSON(t *testing.T) {
+	fences := loadFences(t)
+	if len(fences) != 3 {
+		t.Fatalf("LoadGeoJSON() = %d fences, esperava 3", len(fences))
+	}
+
+	depot, client, islands := fences[0], fences[1], fences[2]
+	if depot.ID != "depot" || depot.Name != "Depot" || client.ID != "client" || islands.ID != "islands" {
+		t.Errorf("IDs = %s, %s, %s", depot.ID, client.ID, islands.ID)
+	}
+
+	tests := []struct {
+		fence *Fence
+		point common.Coordinates
+		want  bool
+	}{
+		{depot, at(-23.52, -46.68), true},
+		{depot, at(-23.55, -46.65), false},
+		{depot, at(-23.40, -46.68), false},
+		{client, at(-23.52, -46.62), true},
+		{client, at(-23.523, -46.62), true},
+		{client, at(-23.53, -46.62), false},
+		{islands, at(-22.99, -43.95), true},
+		{islands, at(-22.99, -44.5), false},
+	}
+	for _, tt := range tests {
+		if got := tt.fence.Contains(tt.point); got != tt.want {
+			t.Errorf("%s.Contains(%v) = %v, esperava %v", tt.fence.ID, tt.point, got, tt.want)
+		}
+	}
+}
+
+func TestLoadGeoJSON_Invalid(t *testing.T) {
+	inputs := []string{
+		`{"type": "FeatureCollection", "features": [{"type": "Feature", "id": "x", "geometry": {"type": "LineString", "coordinates": []}}]}`,
+		`{"type": "Feature", "id": "x", "properties": {}, "geometry": {"type": "Point", "coordinates": [0, 0]}}`,
+		`{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]}}`,
+		`not json`,
+	}
+
+	for _, input := range inputs {
+		if _, err := LoadGeoJSON(strings.NewReader(input)); !errors.Is(err, ErrInvalidGeoJSON) {
+			t.Errorf("LoadGeoJSON(%s) = %v, esperava ErrInvalidGeoJSON", input, err)
+		}
+	}
+}
+
+func TestRTree_MatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	var fences []*Fence
+	for i := 0; i < 2000; i++ {
+		lon, lat := -50+rng.Float64()*10, -25+rng.Float64()*10
+		if i%2 == 0 {
+			fences = append(fences, NewCircle(fmt.Sprint(i), Point{Longitude: lon, Latitude: lat}, 1000+rng.Float64()*20000))
+			continue
+		}
+		size := 0.05 + rng.Float64()*0.2
+		fences = append(fences, NewPolygon(fmt.Sprint(i), []Point{
+			{lon, lat}, {lon + size, lat}, {lon + size, lat + size}, {lon, lat + size}, {lon, lat},
+		}))
+	}
+	m := NewMonitor(fences, Config{})
+
+	for i := 0; i < 500; i++ {
+		point := at(-25+rng.Float64()*10, -50+rng.Float64()*10)
+
+		var want []string
+		for _, f := range fences {
+			if f.Contains(point) {
+				want = append(want, f.ID)
+			}
+		}
+		var got []string
+		for _, f := range m.Fences(point) {
+			got = append(got, f.ID)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("Fences(%v) = %v, esperava %v", point, got, want)
+		}
+	}
+}
+
+func TestMonitor_EnterDwellExit(t *testing.T) {
+	m := NewMonitor(loadFences(t), Config{DwellTime: 10 * time.Minute})
+
+	steps := []struct {
+		event *base.BaseEvent
+		want  string
+	}{
+		{located("e-1", "device-1", 0, -23.40, -46.68), ""},
+		{located("e-2", "device-1", time.Minute, -23.52, -46.62), "GEOFENCE_ENTER:client,GEOFENCE_ENTER:depot"},
+		{located("e-3", "device-1", 5*time.Minute, -23.52, -46.68), "GEOFENCE_EXIT:client"},
+		{located("e-4", "device-1", 12*time.Minute, -23.52, -46.68), "GEOFENCE_DWELL:depot"},
+		{located("e-5", "device-1", 13*time.Minute, -23.52, -46.68), ""},
+		{located("late", "device-1", 2*time.Minute, -23.40, -46.68), ""},
+		{located("e-6", "device-1", 20*time.Minute, -23.40, -46.68), "GEOFENCE_EXIT:depot"},
+	}
+
+	for _, step := range steps {
+		transitions := m.Evaluate(step.event)
+		if got := kinds(transitions); got != step.want {
+			t.Errorf("Evaluate(%s) = %q, esperava %q", step.event.ID, got, step.want)
+		}
+		if step.event.ID == "e-6" && transitions[0].Duration != 19*time.Minute {
+			t.Errorf("Duration = %v, esperava 19m", transitions[0].Duration)
+		}
+	}
+}
+
+func TestMonitor_HandlerEmitsDerivedEvents(t *testing.T) {
+	var derived []*base.BaseEvent
+	m := NewMonitor(loadFences(t), Config{
+		Handler: func(ctx context.Context, event *base.BaseEvent) error {
+			derived = append(derived, event)
+			return nil
+		},
+	})
+	handler := m.Handler()
+
+	if err := handler(context.Background(), located("e-1", "device-1", 0, -23.52, -46.68)); err != nil {
+		t.Fatalf("handler erro inesperado: %v", err)
+	}
+	if len(derived) != 1 {
+		t.Fatalf("eventos derivados = %d, esperava 1", len(derived))
+	}
+
+	event := derived[0]
+	if event.GetEventName() != EventGeofenceEnter || event.GetDeviceID() != "device-1" || event.GetCategory() != "EVENT_CATEGORY_GEOFENCE" {
+		t.Errorf("evento derivado = %+v", event)
+	}
+	if coords := event.GetCoordinates(); coords == nil || coords.Latitude != -23.52 {
+		t.Errorf("GetCoordinates() = %+v, esperava a posição de entrada", coords)
+	}
+
+	if err := handler(context.Background(), event); err != nil || len(derived) != 1 {
+		t.Errorf("evento derivado reprocessado: %d eventos, erro %v", len(derived), err)
+	}
+}
@@ -0,0 +1,99 @@
+package geofence
+
+import (
+	"math"
+	"sort"
+)
+
+const nodeCapacity = 16
+
+type rect struct {
+	minX, minY, maxX, maxY float64
+}
+
+func (r rect) contains(x, y float64) bool {
+	return x >= r.minX && x <= r.maxX && y >= r.minY && y <= r.maxY
+}
+
+func (r rect) union(o rect) rect {
+	return rect{
+		minX: math.Min(r.minX, o.minX),
+		minY: math.Min(r.minY, o.minY),
+		maxX: math.Max(r.maxX, o.maxX),
+		maxY: math.Max(r.maxY, o.maxY),
+	}
+}
+
+func (r rect) centerX() float64 { return (r.minX + r.maxX) / 2 }
+func (r rect) centerY() float64 { return (r.minY + r.maxY) / 2 }
+
+type node struct {
+	bounds   rect
+	children []*node
+	fence    *Fence
+}
+
+// rtree is a static R-tree bulk-loaded with Sort-Tile-Recursive packing.
+type rtree struct {
+	root *node
+}
+
+func newRTree(fences []*Fence) *rtree {
+	if len(fences) == 0 {
+		return &rtree{}
+	}
+
+	level := make([]*node, len(fences))
+	for i, f := range fences {
+		level[i] = &node{bounds: f.bounds, fence: f}
+	}
+	for len(level) > 1 {
+		level = pack(level)
+	}
+	return &rtree{root: level[0]}
+}
+
+func pack(nodes []*node) []*node {
+	leaves := int(math.Ceil(float64(len(nodes)) / nodeCapacity))
+	slices := int(math.Ceil(math.Sqrt(float64(leaves))))
+	sliceSize := slices * nodeCapacity
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].bounds.centerX() < nodes[j].bounds.centerX() })
+
+	var parents []*node
+	for start := 0; start < len(nodes); start += sliceSize {
+		slice := nodes[start:min(start+sliceSize, len(nodes))]
+		sort.Slice(slice, func(i, j int) bool { return slice[i].bounds.centerY() < slice[j].bounds.centerY() })
+
+		for i := 0; i < len(slice); i += nodeCapacity {
+			children := slice[i:min(i+nodeCapacity, len(slice))]
+			parent := &node{bounds: children[0].bounds, children: append([]*node(nil), children...)}
+			for _, child := range children[1:] {
+				parent.bounds = parent.bounds.union(child.bounds)
+			}
+			parents = append(parents, parent)
+		}
+	}
+	return parents
+}
+
+func (t *rtree) search(x, y float64, fn func(f *Fence)) {
+	if t.root == nil {
+		return
+	}
+
+	stack := []*node{t.root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if !n.bounds.contains(x, y) {
+			continue
+		}
+		if n.fence != nil {
+			fn(n.fence)
+			continue
+		}
+		stack = append(stack, n.children...)
+	}
+}
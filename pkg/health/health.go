@@ -0,0 +1,385 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/telemetry"
+)
+
+const EventName = "DEVICE_HEALTH_ALERT"
+
+type Kind string
+
+const (
+	RebootLoop         Kind = "REBOOT_LOOP"
+	SDCardUnmounted    Kind = "SD_CARD_UNMOUNTED"
+	BatteryDisconnects Kind = "VEHICLE_BATTERY_DISCONNECTS"
+)
+
+type Reason struct {
+	Kind     Kind
+	Count    int
+	Since    time.Time
+	Duration time.Duration
+	Penalty  float64
+	EventIDs []string
+}
+
+func (r Reason) String() string {
+	switch r.Kind {
+	case RebootLoop:
+		return fmt.Sprintf("%d reboots since %s", r.Count, r.Since.Format(time.RFC3339))
+	case SDCardUnmounted:
+		return fmt.Sprintf("SD card unmounted for %s", r.Duration.Round(time.Second))
+	case BatteryDisconnects:
+		return fmt.Sprintf("%d vehicle battery disconnects since %s", r.Count, r.Since.Format(time.RFC3339))
+	default:
+		return string(r.Kind)
+	}
+}
+
+type Report struct {
+	DeviceID  string
+	AccountID string
+	Score     float64
+	Reasons   []Reason
+}
+
+func (r Report) Healthy() bool { return len(r.Reasons) == 0 }
+
+type Alert struct {
+	DeviceID  string
+	AccountID string
+	At        time.Time
+	Reason    Reason
+	Score     float64
+}
+
+func (a *Alert) Event() *base.BaseEvent {
+	return &base.BaseEvent{
+		ID:        fmt.Sprintf("health-%s-%s-%d", a.DeviceID, a.Reason.Kind, a.At.UnixNano()),
+		Status:    "STATUS_RECEIVED",
+		CreatedAt: a.At,
+		Type:      "EVENT_TYPE_DERIVED",
+		Category:  "EVENT_CATEGORY_HEALTH",
+		Sub:       "EVENT_SUB_ALERT_WARNING",
+		Attributes: base.Attributes{
+			Device: &base.Device{ID: a.DeviceID, AccountID: a.AccountID},
+			Data: &base.Data{
+				GroupName: "STANDALONE_EVENT",
+				StandaloneEvent: map[string]interface{}{
+					"event_group_name": "ALERT",
+					"alert": map[string]interface{}{
+						"event_name": EventName,
+						"timestamp":  a.At.Format(time.RFC3339Nano),
+						"device_health": map[string]interface{}{
+							"name":      EventName,
+							"reason":    string(a.Reason.Kind),
+							"detail":    a.Reason.String(),
+							"score":     a.Score,
+							"event_ids": a.Reason.EventIDs,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+type Config struct {
+	RebootEvents  []string
+	RebootLimit   int
+	RebootWindow  time.Duration
+	SDCardTimeout time.Duration
+	BatteryLimit  int
+	BatteryWindow time.Duration
+	Penalties     map[Kind]float64
+	Handler       dispatch.Handler
+}
+
+func DefaultConfig() Config {
+	return Config{
+		RebootEvents:  []string{"REBOOT", "R2_RESTART"},
+		RebootLimit:   3,
+		RebootWindow:  30 * time.Minute,
+		SDCardTimeout: time.Hour,
+		BatteryLimit:  3,
+		BatteryWindow: 24 * time.Hour,
+		Penalties: map[Kind]float64{
+			RebootLoop:         40,
+			SDCardUnmounted:    30,
+			BatteryDisconnects: 30,
+		},
+	}
+}
+
+type occurrence struct {
+	at time.Time
+	id string
+}
+
+type device struct {
+	accountID string
+	// latest is the newest event time of the device. Each device is
+	// evaluated on its own clock, so one device's uploads never age another's
+	// occurrences.
+	latest      time.Time
+	reboots     []occurrence
+	disconnects []occurrence
+	unmounted   *occurrence
+	active      map[Kind]Reason
+}
+
+type Analyzer struct {
+	cfg    Config
+	reboot map[string]bool
+
+	mu      sync.Mutex
+	devices map[string]*device
+}
+
+func NewAnalyzer(cfg Config) *Analyzer {
+	defaults := DefaultConfig()
+	if cfg.RebootEvents == nil {
+		cfg.RebootEvents = defaults.RebootEvents
+	}
+	if cfg.RebootLimit <= 0 {
+		cfg.RebootLimit = defaults.RebootLimit
+	}
+	if cfg.RebootWindow <= 0 {
+		cfg.RebootWindow = defaults.RebootWindow
+	}
+	if cfg.SDCardTimeout <= 0 {
+		cfg.SDCardTimeout = defaults.SDCardTimeout
+	}
+	if cfg.BatteryLimit <= 0 {
+		cfg.BatteryLimit = defaults.BatteryLimit
+	}
+	if cfg.BatteryWindow <= 0 {
+		cfg.BatteryWindow = defaults.BatteryWindow
+	}
+	penalties := make(map[Kind]float64, len(defaults.Penalties))
+	for kind, penalty := range defaults.Penalties {
+		penalties[kind] = penalty
+	}
+	for kind, penalty := range cfg.Penalties {
+		penalties[kind] = penalty
+	}
+	cfg.Penalties = penalties
+
+	reboot := make(map[string]bool, len(cfg.RebootEvents))
+	for _, name := range cfg.RebootEvents {
+		reboot[name] = true
+	}
+
+	return &Analyzer{
+		cfg:     cfg,
+		reboot:  reboot,
+		devices: make(map[string]*device),
+	}
+}
+
+func (a *Analyzer) Add(event *base.BaseEvent) []*Alert {
+	deviceID := event.GetDeviceID()
+	if deviceID == "" || event.Type == "EVENT_TYPE_DERIVED" {
+		return nil
+	}
+
+	at := event.GetCreatedAt()
+	o := occurrence{at: at, id: event.GetID()}
+	name := event.GetEventName()
+	disconnect := telemetry.New(event).IsVehicleBatteryDisconnected()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	d, ok := a.devices[deviceID]
+	if !ok {
+		d = &device{active: make(map[Kind]Reason)}
+		a.devices[deviceID] = d
+	}
+	if at.After(d.latest) {
+		d.latest = at
+	}
+	if accountID := event.GetAccountID(); accountID != "" {
+		d.accountID = accountID
+	}
+
+	switch {
+	case a.reboot[name]:
+		d.reboots = insert(d.reboots, o)
+	case name == "SD_CARD_UNMOUNTED":
+		if d.unmounted == nil || at.Before(d.unmounted.at) {
+			d.unmounted = &o
+		}
+	case name == "SD_CARD_MOUNTED":
+		if d.unmounted != nil && !at.Before(d.unmounted.at) {
+			d.unmounted = nil
+		}
+	case disconnect:
+		d.disconnects = insert(d.disconnects, o)
+	default:
+		return nil
+	}
+
+	return a.evaluateLocked(deviceID, d, d.latest)
+}
+
+// Expire re-evaluates every device at now, so an SD card that is never
+// remounted is reported without waiting for the next event.
+func (a *Analyzer) Expire(now time.Time) []*Alert {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ids := make([]string, 0, len(a.devices))
+	for deviceID := range a.devices {
+		ids = append(ids, deviceID)
+	}
+	sort.Strings(ids)
+
+	var alerts []*Alert
+	for _, deviceID := range ids {
+		d := a.devices[deviceID]
+		if now.After(d.latest) {
+			d.latest = now
+		}
+		alerts = append(alerts, a.evaluateLocked(deviceID, d, d.latest)...)
+		if len(d.reboots) == 0 && len(d.disconnects) == 0 && d.unmounted == nil {
+			delete(a.devices, deviceID)
+		}
+	}
+	return alerts
+}
+
+func (a *Analyzer) Report(deviceID string) Report {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	d, ok := a.devices[deviceID]
+	if !ok {
+		return Report{DeviceID: deviceID, Score: 100}
+	}
+	return a.reportLocked(deviceID, d)
+}
+
+func (a *Analyzer) Reports() []Report {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	reports := make([]Report, 0, len(a.devices))
+	for deviceID, d := range a.devices {
+		reports = append(reports, a.reportLocked(deviceID, d))
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Score != reports[j].Score {
+			return reports[i].Score < reports[j].Score
+		}
+		return reports[i].DeviceID < reports[j].DeviceID
+	})
+	return reports
+}
+
+func (a *Analyzer) Handler() dispatch.Handler {
+	return func(ctx context.Context, event *base.BaseEvent) error {
+		alerts := a.Add(event)
+		if a.cfg.Handler == nil {
+			return nil
+		}
+
+		var errs []error
+		for _, alert := range alerts {
+			errs = append(errs, a.cfg.Handler(ctx, alert.Event()))
+		}
+		return errors.Join(errs...)
+	}
+}
+
+func (a *Analyzer) evaluateLocked(deviceID string, d *device, now time.Time) []*Alert {
+	d.reboots = prune(d.reboots, now.Add(-a.cfg.RebootWindow))
+	d.disconnects = prune(d.disconnects, now.Add(-a.cfg.BatteryWindow))
+
+	current := make(map[Kind]Reason)
+	if len(d.reboots) >= a.cfg.RebootLimit {
+		current[RebootLoop] = a.reason(RebootLoop, d.reboots)
+	}
+	if len(d.disconnects) >= a.cfg.BatteryLimit {
+		current[BatteryDisconnects] = a.reason(BatteryDisconnects, d.disconnects)
+	}
+	if d.unmounted != nil && now.Sub(d.unmounted.at) >= a.cfg.SDCardTimeout {
+		reason := a.reason(SDCardUnmounted, []occurrence{*d.unmounted})
+		reason.Duration = now.Sub(d.unmounted.at)
+		current[SDCardUnmounted] = reason
+	}
+
+	var raised []Kind
+	for kind := range current {
+		if _, ok := d.active[kind]; !ok {
+			raised = append(raised, kind)
+		}
+	}
+	d.active = current
+
+	if len(raised) == 0 {
+		return nil
+	}
+	sort.Slice(raised, func(i, j int) bool { return raised[i] < raised[j] })
+
+	score := a.reportLocked(deviceID, d).Score
+	alerts := make([]*Alert, 0, len(raised))
+	for _, kind := range raised {
+		alerts = append(alerts, &Alert{
+			DeviceID:  deviceID,
+			AccountID: d.accountID,
+			At:        now,
+			Reason:    current[kind],
+			Score:     score,
+		})
+	}
+	return alerts
+}
+
+func (a *Analyzer) reportLocked(deviceID string, d *device) Report {
+	report := Report{DeviceID: deviceID, AccountID: d.accountID, Score: 100}
+	for _, reason := range d.active {
+		report.Reasons = append(report.Reasons, reason)
+		report.Score -= reason.Penalty
+	}
+	if report.Score < 0 {
+		report.Score = 0
+	}
+	sort.Slice(report.Reasons, func(i, j int) bool { return report.Reasons[i].Kind < report.Reasons[j].Kind })
+	return report
+}
+
+func (a *Analyzer) reason(kind Kind, occurrences []occurrence) Reason {
+	reason := Reason{
+		Kind:    kind,
+		Count:   len(occurrences),
+		Since:   occurrences[0].at,
+		Penalty: a.cfg.Penalties[kind],
+	}
+	for _, o := range occurrences {
+		reason.EventIDs = append(reason.EventIDs, o.id)
+	}
+	return reason
+}
+
+func insert(occurrences []occurrence, o occurrence) []occurrence {
+	i := sort.Search(len(occurrences), func(i int) bool { return occurrences[i].at.After(o.at) })
+	occurrences = append(occurrences, occurrence{})
+	copy(occurrences[i+1:], occurrences[i:])
+	occurrences[i] = o
+	return occurrences
+}
+
+func prune(occurrences []occurrence, cutoff time.Time) []occurrence {
+	i := sort.Search(len(occurrences), func(i int) bool { return occurrences[i].at.After(cutoff) })
+	return occurrences[i:]
+}
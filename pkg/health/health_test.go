@@ -0,0 +1,139 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-eventlib/pkg/eventio"
+	"go-eventlib/pkg/types/base"
+)
+
+var start = time.Date(2025, 12, 15, 19, 0, 0, 0, time.UTC)
+
+func fixture(t *testing.T, name string, offset time.Duration) *base.BaseEvent {
+	t.Helper()
+
+	for event, err := range eventio.ReadFile("../../test/events/hardware-events/" + name + ".json") {
+		if err != nil {
+			t.Fatalf("ReadFile(%s) erro inesperado: %v", name, err)
+		}
+		event.ID = fmt.Sprintf("%s-%d", name, offset)
+		event.Attributes.Device.ID = "device-1"
+		event.CreatedAt = start.Add(offset)
+		return event
+	}
+	t.Fatalf("ReadFile(%s) não retornou eventos", name)
+	return nil
+}
+
+func TestAnalyzer_RebootLoop(t *testing.T) {
+	a := NewAnalyzer(Config{RebootLimit: 3, RebootWindow: 10 * time.Minute})
+
+	a.Add(fixture(t, "hardware-reboot", 0))
+	a.Add(fixture(t, "hardware-r2-restart", 4*time.Minute))
+	if report := a.Report("device-1"); !report.Healthy() || report.Score != 100 {
+		t.Fatalf("Report() = %+v, esperava saudável com 2 reboots", report)
+	}
+
+	alerts := a.Add(fixture(t, "hardware-reboot", 8*time.Minute))
+	if len(alerts) != 1 || alerts[0].Reason.Kind != RebootLoop || alerts[0].Reason.Count != 3 {
+		t.Fatalf("alerts = %+v, esperava REBOOT_LOOP com 3 reboots", alerts)
+	}
+	if alerts[0].Score != 60 || len(alerts[0].Reason.EventIDs) != 3 {
+		t.Errorf("alert = %+v, esperava score 60 e 3 eventos", alerts[0])
+	}
+
+	if again := a.Add(fixture(t, "hardware-reboot", 9*time.Minute)); len(again) != 0 {
+		t.Errorf("Add() repetiu o alerta: %+v", again)
+	}
+
+	a.Expire(start.Add(time.Hour))
+	if report := a.Report("device-1"); !report.Healthy() {
+		t.Errorf("Report() = %+v, esperava recuperação após a janela", report)
+	}
+}
+
+func TestAnalyzer_SDCardUnmountedTooLong(t *testing.T) {
+	a := NewAnalyzer(Config{SDCardTimeout: 30 * time.Minute})
+
+	a.Add(fixture(t, "hardware-sdcard-unmounted", 0))
+	if alerts := a.Expire(start.Add(20 * time.Minute)); len(alerts) != 0 {
+		t.Fatalf("Expire() antes do limite = %+v", alerts)
+	}
+
+	alerts := a.Expire(start.Add(45 * time.Minute))
+	if len(alerts) != 1 || alerts[0].Reason.Kind != SDCardUnmounted || alerts[0].Reason.Duration != 45*time.Minute {
+		t.Fatalf("alerts = %+v, esperava SD_CARD_UNMOUNTED após 45m", alerts)
+	}
+
+	a.Add(fixture(t, "hardware-sdcard-mounted", 50*time.Minute))
+	if report := a.Report("device-1"); !report.Healthy() {
+		t.Errorf("Report() = %+v, esperava saudável após montar o cartão", report)
+	}
+}
+
+func TestAnalyzer_EvaluatesEachDeviceOnItsOwnClock(t *testing.T) {
+	a := NewAnalyzer(Config{SDCardTimeout: 30 * time.Minute})
+
+	a.Add(fixture(t, "hardware-sdcard-unmounted", 0))
+
+	// A reboot of another device two hours later must not age device-1.
+	other := fixture(t, "hardware-reboot", 2*time.Hour)
+	other.Attributes.Device.ID = "device-2"
+	if alerts := a.Add(other); len(alerts) != 0 {
+		t.Fatalf("Add() de outro dispositivo = %+v, esperava nenhum alerta", alerts)
+	}
+	if report := a.Report("device-1"); !report.Healthy() {
+		t.Fatalf("Report() = %+v, esperava device-1 saudável", report)
+	}
+
+	alerts := a.Add(fixture(t, "hardware-reboot", 40*time.Minute))
+	if len(alerts) != 1 || alerts[0].Reason.Kind != SDCardUnmounted || alerts[0].Reason.Duration != 40*time.Minute {
+		t.Errorf("alerts = %+v, esperava SD_CARD_UNMOUNTED após 40m do próprio dispositivo", alerts)
+	}
+}
+
+func TestAnalyzer_RepeatedBatteryDisconnectsAndScore(t *testing.T) {
+	var emitted []*base.BaseEvent
+	a := NewAnalyzer(Config{
+		BatteryLimit: 2,
+		RebootLimit:  2,
+		Handler: func(ctx context.Context, event *base.BaseEvent) error {
+			emitted = append(emitted, event)
+			return nil
+		},
+	})
+	handler := a.Handler()
+
+	for i, name := range []string{
+		"hardware-vehicle-battery-disconnected",
+		"hardware-vehicle-battery-connected",
+		"hardware-vehicle-battery-disconnected",
+		"hardware-reboot",
+		"hardware-reboot",
+	} {
+		if err := handler(context.Background(), fixture(t, name, time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("handler() erro inesperado: %v", err)
+		}
+	}
+
+	if len(emitted) != 2 {
+		t.Fatalf("len(emitted) = %d, esperava 2 alertas", len(emitted))
+	}
+	if emitted[0].GetEventName() != EventName || emitted[0].GetCategory() != "EVENT_CATEGORY_HEALTH" {
+		t.Errorf("Event() = %+v", emitted[0])
+	}
+
+	report := a.Report("device-1")
+	if report.Score != 30 || len(report.Reasons) != 2 {
+		t.Fatalf("Report() = %+v, esperava score 30 com 2 motivos", report)
+	}
+	if report.Reasons[0].Kind != RebootLoop || report.Reasons[1].Kind != BatteryDisconnects {
+		t.Errorf("Reasons = %+v", report.Reasons)
+	}
+	if reports := a.Reports(); len(reports) != 1 || reports[0].DeviceID != "device-1" {
+		t.Errorf("Reports() = %+v", reports)
+	}
+}
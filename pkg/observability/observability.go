@@ -0,0 +1,165 @@
+package observability
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/webhook"
+)
+
+const instrumentationName = "go-eventlib"
+
+var _ webhook.Observer = (*Instrumentation)(nil)
+
+type Stage string
+
+const (
+	StageParse    Stage = "parse"
+	StageValidate Stage = "validate"
+	StageDispatch Stage = "dispatch"
+	StageHandler  Stage = "handler"
+)
+
+type Instrumentation struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+
+	received      metric.Int64Counter
+	handlerErrors metric.Int64Counter
+	ruleMatches   metric.Int64Counter
+	latency       metric.Float64Histogram
+}
+
+func New(tp trace.TracerProvider, mp metric.MeterProvider) (*Instrumentation, error) {
+	meter := mp.Meter(instrumentationName)
+
+	received, err := meter.Int64Counter("eventlib.events.received",
+		metric.WithDescription("Number of events received"),
+		metric.WithUnit("{event}"))
+	if err != nil {
+		return nil, err
+	}
+
+	handlerErrors, err := meter.Int64Counter("eventlib.handler.errors",
+		metric.WithDescription("Number of handler calls that returned an error"),
+		metric.WithUnit("{error}"))
+	if err != nil {
+		return nil, err
+	}
+
+	ruleMatches, err := meter.Int64Counter("eventlib.rules.matched",
+		metric.WithDescription("Number of events matched by a rule"),
+		metric.WithUnit("{match}"))
+	if err != nil {
+		return nil, err
+	}
+
+	latency, err := meter.Float64Histogram("eventlib.event.latency",
+		metric.WithDescription("Time from event created_at to processing"),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Instrumentation{
+		tracer:        tp.Tracer(instrumentationName),
+		propagator:    propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+		received:      received,
+		handlerErrors: handlerErrors,
+		ruleMatches:   ruleMatches,
+		latency:       latency,
+	}, nil
+}
+
+func (i *Instrumentation) Extract(ctx context.Context, header http.Header) context.Context {
+	return i.propagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+func (i *Instrumentation) Start(ctx context.Context, stage Stage, event *base.BaseEvent) (context.Context, trace.Span) {
+	ctx, span := i.tracer.Start(ctx, "eventlib."+string(stage), trace.WithSpanKind(trace.SpanKindInternal))
+	if event != nil {
+		span.SetAttributes(EventAttributes(event)...)
+	}
+	return ctx, span
+}
+
+// ObserveStage implements webhook.Observer. The span is tagged with the
+// event once the stage ends; a successful parse also counts as a received
+// event.
+func (i *Instrumentation) ObserveStage(ctx context.Context, stage string) (context.Context, func(*base.BaseEvent, error)) {
+	ctx, span := i.Start(ctx, Stage(stage), nil)
+	return ctx, func(event *base.BaseEvent, err error) {
+		defer span.End()
+
+		if event != nil {
+			span.SetAttributes(EventAttributes(event)...)
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return
+		}
+		if Stage(stage) == StageParse {
+			i.RecordReceived(ctx, event)
+		}
+	}
+}
+
+func (i *Instrumentation) RecordReceived(ctx context.Context, event *base.BaseEvent) {
+	i.received.Add(ctx, 1, metric.WithAttributes(metricAttributes(event)...))
+
+	if createdAt := event.GetCreatedAt(); !createdAt.IsZero() {
+		i.latency.Record(ctx, time.Since(createdAt).Seconds(), metric.WithAttributes(metricAttributes(event)...))
+	}
+}
+
+// RecordRuleMatch matches webhook.HandlerProcessor.OnRuleMatch.
+func (i *Instrumentation) RecordRuleMatch(ctx context.Context, ruleID string, event *base.BaseEvent) {
+	attrs := append(metricAttributes(event), attribute.String("rule.id", ruleID))
+	i.ruleMatches.Add(ctx, 1, metric.WithAttributes(attrs...))
+	trace.SpanFromContext(ctx).AddEvent("rule.matched", trace.WithAttributes(attribute.String("rule.id", ruleID)))
+}
+
+func (i *Instrumentation) Middleware() dispatch.Middleware {
+	return func(next dispatch.Handler) dispatch.Handler {
+		return func(ctx context.Context, event *base.BaseEvent) error {
+			ctx, span := i.Start(ctx, StageHandler, event)
+			defer span.End()
+
+			err := next(ctx, event)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				i.handlerErrors.Add(ctx, 1, metric.WithAttributes(metricAttributes(event)...))
+			}
+
+			return err
+		}
+	}
+}
+
+func EventAttributes(event *base.BaseEvent) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("event.id", event.GetID()),
+		attribute.String("event.category", string(event.GetCategory())),
+		attribute.String("event.name", event.GetEventName()),
+		attribute.String("device.id", event.GetDeviceID()),
+		attribute.String("account.id", event.GetAccountID()),
+	}
+}
+
+func metricAttributes(event *base.BaseEvent) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("event.category", string(event.GetCategory())),
+		attribute.String("event.name", event.GetEventName()),
+	}
+}
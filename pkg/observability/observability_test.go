@@ -0,0 +1,265 @@
+package observability
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/webhook"
+)
+
+func setup(t *testing.T) (*Instrumentation, *tracetest.InMemoryExporter, *sdkmetric.ManualReader) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	inst, err := New(tp, mp)
+	if err != nil {
+		t.Fatalf("New() erro inesperado: %v", err)
+	}
+	return inst, exporter, reader
+}
+
+func newEvent() *base.BaseEvent {
+	return &base.BaseEvent{
+		ID:        "event-1",
+		Category:  "EVENT_CATEGORY_DMS",
+		CreatedAt: time.Now().Add(-2 * time.Second),
+		Attributes: base.Attributes{
+			Device: &base.Device{ID: "device-1", AccountID: "account-1"},
+			Data: &base.Data{
+				TripEvent: map[string]interface{}{
+					"trip_id": "trip-1",
+					"dms":     map[string]interface{}{"event_name": "DROWSINESS"},
+				},
+			},
+		},
+	}
+}
+
+func attrValue(attrs []attribute.KeyValue, key attribute.Key) string {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestMiddleware_RecordsHandlerSpan(t *testing.T) {
+	inst, exporter, reader := setup(t)
+	handlerErr := errors.New("falha")
+
+	handler := dispatch.Chain(func(ctx context.Context, event *base.BaseEvent) error {
+		return handlerErr
+	}, inst.Middleware())
+	handler(context.Background(), newEvent())
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("spans = %d, esperava 1", len(spans))
+	}
+
+	span := spans[0]
+	if span.Name != "eventlib.handler" {
+		t.Errorf("span.Name = %s, esperava eventlib.handler", span.Name)
+	}
+	if span.Status.Code != codes.Error {
+		t.Errorf("span.Status = %v, esperava Error", span.Status.Code)
+	}
+
+	expected := map[attribute.Key]string{
+		"event.id":       "event-1",
+		"event.category": "EVENT_CATEGORY_DMS",
+		"event.name":     "DROWSINESS",
+		"device.id":      "device-1",
+		"account.id":     "account-1",
+	}
+	for key, want := range expected {
+		if got := attrValue(span.Attributes, key); got != want {
+			t.Errorf("atributo %s = %s, esperava %s", key, got, want)
+		}
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() erro inesperado: %v", err)
+	}
+
+	sum, ok := findMetric(rm, "eventlib.handler.errors").(metricdata.Sum[int64])
+	if !ok || len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 1 {
+		t.Errorf("eventlib.handler.errors = %+v, esperava 1", sum)
+	}
+}
+
+func TestRecordReceived(t *testing.T) {
+	inst, _, reader := setup(t)
+
+	inst.RecordReceived(context.Background(), newEvent())
+	inst.RecordReceived(context.Background(), newEvent())
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() erro inesperado: %v", err)
+	}
+
+	sum, ok := findMetric(rm, "eventlib.events.received").(metricdata.Sum[int64])
+	if !ok || len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 2 {
+		t.Errorf("eventlib.events.received = %+v, esperava 2", sum)
+	}
+
+	hist, ok := findMetric(rm, "eventlib.event.latency").(metricdata.Histogram[float64])
+	if !ok || len(hist.DataPoints) != 1 {
+		t.Fatalf("eventlib.event.latency = %+v, esperava 1 ponto", hist)
+	}
+	if hist.DataPoints[0].Count != 2 || hist.DataPoints[0].Sum < 4 {
+		t.Errorf("latência: count = %d, sum = %f, esperava 2 e >= 4s", hist.DataPoints[0].Count, hist.DataPoints[0].Sum)
+	}
+}
+
+func TestRecordRuleMatch(t *testing.T) {
+	inst, exporter, reader := setup(t)
+
+	ctx, span := inst.Start(context.Background(), StageHandler, newEvent())
+	inst.RecordRuleMatch(ctx, "drowsy-at-speed", newEvent())
+	span.End()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() erro inesperado: %v", err)
+	}
+
+	sum, ok := findMetric(rm, "eventlib.rules.matched").(metricdata.Sum[int64])
+	if !ok || len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 1 {
+		t.Fatalf("eventlib.rules.matched = %+v, esperava 1", sum)
+	}
+	if got, _ := sum.DataPoints[0].Attributes.Value("rule.id"); got.AsString() != "drowsy-at-speed" {
+		t.Errorf("rule.id = %s, esperava drowsy-at-speed", got.AsString())
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || len(spans[0].Events) != 1 || spans[0].Events[0].Name != "rule.matched" {
+		t.Errorf("span events = %+v, esperava rule.matched", spans)
+	}
+}
+
+func TestExtract_ContinuesIncomingTrace(t *testing.T) {
+	inst, exporter, _ := setup(t)
+
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	ctx := inst.Extract(context.Background(), header)
+	_, span := inst.Start(ctx, StageParse, nil)
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("spans = %d, esperava 1", len(spans))
+	}
+	if got := spans[0].SpanContext.TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("TraceID = %s, esperava 4bf92f3577b34da6a3ce929d0e0e4736", got)
+	}
+	if got := spans[0].Parent.SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("Parent.SpanID = %s, esperava 00f067aa0ba902b7", got)
+	}
+	if spans[0].SpanKind != trace.SpanKindInternal {
+		t.Errorf("SpanKind = %v, esperava Internal", spans[0].SpanKind)
+	}
+}
+
+func TestObserveStage_WiredIntoWebhook(t *testing.T) {
+	inst, exporter, reader := setup(t)
+
+	processor := webhook.NewHandlerProcessor(dispatch.Chain(func(ctx context.Context, event *base.BaseEvent) error {
+		return nil
+	}, inst.Middleware())).WithObserver(inst)
+	handler := webhook.NewHTTPHandler(processor, webhook.WithTraceExtractor(inst.Extract))
+
+	body, err := os.ReadFile("../../test/events/dms-events/vision-drowsiness.json")
+	if err != nil {
+		t.Fatalf("ReadFile() erro inesperado: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, esperava 202", rec.Code)
+	}
+
+	spans := exporter.GetSpans()
+	var names []string
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range spans.Snapshots() {
+		names = append(names, span.Name())
+		byName[span.Name()] = span
+	}
+	if got := strings.Join(names, ","); got != "eventlib.parse,eventlib.validate,eventlib.handler,eventlib.dispatch" {
+		t.Fatalf("spans = %s", got)
+	}
+	for _, span := range spans {
+		if got := span.SpanContext.TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("%s: TraceID = %s, esperava o trace recebido", span.Name, got)
+		}
+		if got := attrValue(span.Attributes, "event.name"); got != "DROWSINESS" {
+			t.Errorf("%s: event.name = %s, esperava DROWSINESS", span.Name, got)
+		}
+	}
+	if byName["eventlib.handler"].Parent().SpanID() != byName["eventlib.dispatch"].SpanContext().SpanID() {
+		t.Errorf("span do handler deveria ser filho do span de dispatch")
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() erro inesperado: %v", err)
+	}
+	sum, ok := findMetric(rm, "eventlib.events.received").(metricdata.Sum[int64])
+	if !ok || len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 1 {
+		t.Errorf("eventlib.events.received = %+v, esperava 1", sum)
+	}
+}
+
+func TestObserveStage_RecordsStageError(t *testing.T) {
+	inst, exporter, _ := setup(t)
+
+	processor := webhook.NewHandlerProcessor(func(ctx context.Context, event *base.BaseEvent) error {
+		return nil
+	}).WithObserver(inst)
+	if _, err := processor.ProcessEvent(context.Background(), []byte(`{"id":"x"}`)); !errors.Is(err, webhook.ErrValidation) {
+		t.Fatalf("ProcessEvent() erro = %v, esperava ErrValidation", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 || spans[1].Name != "eventlib.validate" || spans[1].Status.Code != codes.Error {
+		t.Errorf("spans = %+v, esperava validate com status Error", spans)
+	}
+}
+
+func findMetric(rm metricdata.ResourceMetrics, name string) metricdata.Aggregation {
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m.Data
+			}
+		}
+	}
+	return nil
+}
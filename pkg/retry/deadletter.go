@@ -0,0 +1,262 @@
+package retry
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/types/base"
+)
+
+type Entry struct {
+	ID            string          `json:"id"`
+	Handler       string          `json:"handler"`
+	Event         *base.BaseEvent `json:"event"`
+	Errors        []string        `json:"errors"`
+	Attempts      int             `json:"attempts"`
+	FirstFailedAt time.Time       `json:"first_failed_at"`
+	LastFailedAt  time.Time       `json:"last_failed_at"`
+}
+
+func NewEntry(handler string, event *base.BaseEvent, err error, attempts int) *Entry {
+	now := time.Now()
+	return &Entry{
+		ID:            entryID(handler, event),
+		Handler:       handler,
+		Event:         event,
+		Errors:        errorChain(err),
+		Attempts:      attempts,
+		FirstFailedAt: now,
+		LastFailedAt:  now,
+	}
+}
+
+func entryID(handler string, event *base.BaseEvent) string {
+	if handler == "" {
+		return event.GetID()
+	}
+	return handler + "/" + event.GetID()
+}
+
+func errorChain(err error) []string {
+	var chain []string
+	queue := []error{err}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == nil {
+			continue
+		}
+
+		chain = append(chain, current.Error())
+
+		switch e := current.(type) {
+		case interface{ Unwrap() []error }:
+			queue = append(queue, e.Unwrap()...)
+		case interface{ Unwrap() error }:
+			queue = append(queue, e.Unwrap())
+		}
+	}
+
+	return chain
+}
+
+type DeadLetterStore interface {
+	Put(ctx context.Context, entry *Entry) error
+	List(ctx context.Context) ([]*Entry, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries []*Entry
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) Put(ctx context.Context, entry *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = upsert(s.entries, entry)
+	return nil
+}
+
+func (s *MemoryStore) List(ctx context.Context) ([]*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*Entry(nil), s.entries...), nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = remove(s.entries, id)
+	return nil
+}
+
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+func (s *FileStore) Put(ctx context.Context, entry *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	for _, existing := range entries {
+		if existing.ID == entry.ID {
+			return s.write(upsert(entries, entry))
+		}
+	}
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("dead letter: opening %s: %w", s.path, err)
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(entry)
+}
+
+func (s *FileStore) List(ctx context.Context) ([]*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.read()
+}
+
+func (s *FileStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	return s.write(remove(entries, id))
+}
+
+func (s *FileStore) read() ([]*Entry, error) {
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dead letter: opening %s: %w", s.path, err)
+	}
+	defer f.Close()
+
+	var entries []*Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("dead letter: decoding %s: %w", s.path, err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+func (s *FileStore) write(entries []*Entry) error {
+	tmp := s.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("dead letter: creating %s: %w", tmp, err)
+	}
+
+	enc := json.NewEncoder(f)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.path)
+}
+
+func upsert(entries []*Entry, entry *Entry) []*Entry {
+	for i, existing := range entries {
+		if existing.ID == entry.ID {
+			entry.Attempts += existing.Attempts
+			entry.FirstFailedAt = existing.FirstFailedAt
+			entries[i] = entry
+			return entries
+		}
+	}
+	return append(entries, entry)
+}
+
+func remove(entries []*Entry, id string) []*Entry {
+	kept := entries[:0]
+	for _, entry := range entries {
+		if entry.ID != id {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+func Redrive(ctx context.Context, store DeadLetterStore, name string, handler dispatch.Handler) (int, error) {
+	entries, err := store.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	redriven := 0
+
+	for _, entry := range entries {
+		if name != "" && entry.Handler != name {
+			continue
+		}
+
+		if err := handler(ctx, entry.Event); err != nil {
+			errs = append(errs, fmt.Errorf("redrive %s: %w", entry.ID, err))
+			if putErr := store.Put(ctx, NewEntry(entry.Handler, entry.Event, err, 1)); putErr != nil {
+				errs = append(errs, putErr)
+			}
+			continue
+		}
+
+		if err := store.Delete(ctx, entry.ID); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		redriven++
+	}
+
+	return redriven, errors.Join(errs...)
+}
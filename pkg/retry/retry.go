@@ -0,0 +1,147 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/types/base"
+)
+
+// ErrDeadLettered is returned, wrapping the handler error, once an exhausted
+// event has been stored in the dead-letter store.
+var ErrDeadLettered = errors.New("retry: event dead-lettered")
+
+type Policy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	Jitter         float64
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+func (p Policy) Backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		backoff += backoff * p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(backoff)
+}
+
+type Retryable interface {
+	Retryable() bool
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string   { return e.err.Error() }
+func (e *permanentError) Unwrap() error   { return e.err }
+func (e *permanentError) Retryable() bool { return false }
+
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+func IsRetryable(err error) bool {
+	var r Retryable
+	if errors.As(err, &r) {
+		return r.Retryable()
+	}
+	return true
+}
+
+type Config struct {
+	Name   string
+	Policy Policy
+	Store  DeadLetterStore
+}
+
+func Wrap(handler dispatch.Handler, cfg Config) dispatch.Handler {
+	if cfg.Policy.MaxAttempts <= 0 {
+		cfg.Policy.MaxAttempts = 1
+	}
+
+	return func(ctx context.Context, event *base.BaseEvent) error {
+		var err error
+		var firstFailure time.Time
+		attempts := 0
+
+		for {
+			attempts++
+
+			if err = handler(ctx, event); err == nil {
+				return nil
+			}
+			if firstFailure.IsZero() {
+				firstFailure = time.Now()
+			}
+			if !IsRetryable(err) || attempts >= cfg.Policy.MaxAttempts {
+				break
+			}
+			if waitErr := sleep(ctx, cfg.Policy.Backoff(attempts)); waitErr != nil {
+				err = errors.Join(err, waitErr)
+				break
+			}
+		}
+
+		if cfg.Store == nil {
+			return err
+		}
+
+		entry := NewEntry(cfg.Name, event, err, attempts)
+		entry.FirstFailedAt = firstFailure
+		if storeErr := cfg.Store.Put(context.WithoutCancel(ctx), entry); storeErr != nil {
+			return errors.Join(err, storeErr)
+		}
+
+		return Permanent(fmt.Errorf("%w: %w", ErrDeadLettered, err))
+	}
+}
+
+// Middleware applies Wrap to every handler in a dispatch chain.
+func Middleware(cfg Config) dispatch.Middleware {
+	return func(next dispatch.Handler) dispatch.Handler {
+		return Wrap(next, cfg)
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
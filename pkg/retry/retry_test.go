@@ -0,0 +1,234 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/types/base"
+)
+
+func testPolicy(attempts int) Policy {
+	return Policy{
+		MaxAttempts:    attempts,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		Multiplier:     2,
+	}
+}
+
+func TestPolicy_Backoff(t *testing.T) {
+	policy := Policy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{5, time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := policy.Backoff(tt.attempt); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, esperava %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestPolicy_BackoffJitter(t *testing.T) {
+	policy := Policy{InitialBackoff: 100 * time.Millisecond, Multiplier: 2, Jitter: 0.5}
+
+	for i := 0; i < 100; i++ {
+		got := policy.Backoff(1)
+		if got < 50*time.Millisecond || got > 150*time.Millisecond {
+			t.Fatalf("Backoff(1) = %v, esperava entre 50ms e 150ms", got)
+		}
+	}
+}
+
+func TestWrap_RetriesUntilSuccess(t *testing.T) {
+	calls := 0
+	handler := Wrap(func(ctx context.Context, event *base.BaseEvent) error {
+		calls++
+		if calls < 3 {
+			return errors.New("temporário")
+		}
+		return nil
+	}, Config{Policy: testPolicy(5)})
+
+	if err := handler(context.Background(), &base.BaseEvent{ID: "event-1"}); err != nil {
+		t.Errorf("handler retornou erro inesperado: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("tentativas = %d, esperava 3", calls)
+	}
+}
+
+func TestWrap_StopsOnPermanentError(t *testing.T) {
+	calls := 0
+	handlerErr := errors.New("payload inválido")
+	handler := Wrap(func(ctx context.Context, event *base.BaseEvent) error {
+		calls++
+		return Permanent(handlerErr)
+	}, Config{Policy: testPolicy(5)})
+
+	err := handler(context.Background(), &base.BaseEvent{ID: "event-1"})
+	if !errors.Is(err, handlerErr) {
+		t.Errorf("handler retornou %v, esperava %v", err, handlerErr)
+	}
+	if calls != 1 {
+		t.Errorf("tentativas = %d, esperava 1", calls)
+	}
+}
+
+func TestWrap_DeadLettersExhaustedEvents(t *testing.T) {
+	store := NewMemoryStore()
+	rootErr := errors.New("timeout")
+	handler := Wrap(func(ctx context.Context, event *base.BaseEvent) error {
+		return fmt.Errorf("OnDrowsiness: %w", rootErr)
+	}, Config{Name: "drowsiness", Policy: testPolicy(3), Store: store})
+
+	event := &base.BaseEvent{ID: "event-1"}
+	err := handler(context.Background(), event)
+	if !errors.Is(err, ErrDeadLettered) || !errors.Is(err, rootErr) || IsRetryable(err) {
+		t.Errorf("handler retornou %v, esperava ErrDeadLettered permanente envolvendo %v", err, rootErr)
+	}
+
+	entries, _ := store.List(context.Background())
+	if len(entries) != 1 {
+		t.Fatalf("entradas = %d, esperava 1", len(entries))
+	}
+
+	entry := entries[0]
+	if entry.ID != "drowsiness/event-1" {
+		t.Errorf("Entry.ID = %s, esperava drowsiness/event-1", entry.ID)
+	}
+	if entry.Attempts != 3 {
+		t.Errorf("Entry.Attempts = %d, esperava 3", entry.Attempts)
+	}
+	if len(entry.Errors) != 2 || entry.Errors[0] != "OnDrowsiness: timeout" || entry.Errors[1] != "timeout" {
+		t.Errorf("Entry.Errors = %v, esperava cadeia [OnDrowsiness: timeout, timeout]", entry.Errors)
+	}
+	if entry.Event != event {
+		t.Error("Entry.Event não corresponde ao evento original")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	calls := 0
+	handler := dispatch.Chain(func(ctx context.Context, event *base.BaseEvent) error {
+		calls++
+		if calls < 2 {
+			return errors.New("temporário")
+		}
+		return nil
+	}, Middleware(Config{Policy: testPolicy(3)}))
+
+	if err := handler(context.Background(), &base.BaseEvent{ID: "event-1"}); err != nil {
+		t.Errorf("handler retornou erro inesperado: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("tentativas = %d, esperava 2", calls)
+	}
+}
+
+func TestWrap_ContextCanceledDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	handler := Wrap(func(ctx context.Context, event *base.BaseEvent) error {
+		calls++
+		cancel()
+		return errors.New("falha")
+	}, Config{Policy: Policy{MaxAttempts: 5, InitialBackoff: time.Hour}})
+
+	err := handler(ctx, &base.BaseEvent{ID: "event-1"})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("handler retornou %v, esperava context.Canceled", err)
+	}
+	if calls != 1 {
+		t.Errorf("tentativas = %d, esperava 1", calls)
+	}
+}
+
+func TestFileStore_PutListDelete(t *testing.T) {
+	store := NewFileStore(t.TempDir() + "/dead-letters.jsonl")
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		event := &base.BaseEvent{ID: fmt.Sprintf("event-%d", i)}
+		if err := store.Put(ctx, NewEntry("dms", event, errors.New("falha"), 2)); err != nil {
+			t.Fatalf("Put() erro inesperado: %v", err)
+		}
+	}
+
+	entries, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List() erro inesperado: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("entradas = %d, esperava 3", len(entries))
+	}
+	if entries[1].Event.ID != "event-1" || entries[1].Attempts != 2 {
+		t.Errorf("entrada 1 = %+v, esperava event-1 com 2 tentativas", entries[1])
+	}
+
+	if err := store.Put(ctx, NewEntry("dms", &base.BaseEvent{ID: "event-1"}, errors.New("falha"), 1)); err != nil {
+		t.Fatalf("Put() erro inesperado: %v", err)
+	}
+	if err := store.Delete(ctx, "dms/event-0"); err != nil {
+		t.Fatalf("Delete() erro inesperado: %v", err)
+	}
+
+	entries, _ = store.List(ctx)
+	if len(entries) != 2 {
+		t.Fatalf("entradas = %d, esperava 2", len(entries))
+	}
+	if entries[0].ID != "dms/event-1" || entries[0].Attempts != 3 {
+		t.Errorf("entrada 0 = %s com %d tentativas, esperava dms/event-1 com 3", entries[0].ID, entries[0].Attempts)
+	}
+}
+
+func TestRedrive(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	store.Put(ctx, NewEntry("dms", &base.BaseEvent{ID: "ok"}, errors.New("falha"), 3))
+	store.Put(ctx, NewEntry("dms", &base.BaseEvent{ID: "still-failing"}, errors.New("falha"), 3))
+	store.Put(ctx, NewEntry("vision", &base.BaseEvent{ID: "other"}, errors.New("falha"), 3))
+
+	redriven, err := Redrive(ctx, store, "dms", func(ctx context.Context, event *base.BaseEvent) error {
+		if event.ID == "still-failing" {
+			return errors.New("ainda falhando")
+		}
+		return nil
+	})
+
+	if redriven != 1 {
+		t.Errorf("Redrive() = %d, esperava 1", redriven)
+	}
+	if err == nil {
+		t.Error("Redrive() deveria retornar o erro do evento que falhou")
+	}
+
+	entries, _ := store.List(ctx)
+	if len(entries) != 2 {
+		t.Fatalf("entradas = %d, esperava 2", len(entries))
+	}
+	for _, entry := range entries {
+		if entry.ID == "dms/still-failing" && entry.Attempts != 4 {
+			t.Errorf("Entry.Attempts = %d, esperava 4", entry.Attempts)
+		}
+		if entry.ID == "dms/ok" {
+			t.Error("entrada reprocessada com sucesso não foi removida")
+		}
+	}
+}
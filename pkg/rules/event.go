@@ -0,0 +1,79 @@
+package rules
+
+import (
+	"strings"
+
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/dms"
+	"go-eventlib/pkg/types/telemetry"
+)
+
+// fields builds the value bound to the `event` variable. Optional groups
+// (location, dms, telemetry) are only present when the event carries them,
+// so expressions must guard them with has(event.location).
+func fields(event *base.BaseEvent) map[string]interface{} {
+	f := map[string]interface{}{
+		"id":         event.GetID(),
+		"category":   strings.TrimPrefix(string(event.GetCategory()), "EVENT_CATEGORY_"),
+		"sub":        strings.TrimPrefix(string(event.GetSubType()), "EVENT_SUB_"),
+		"type":       string(event.Type),
+		"status":     string(event.Status),
+		"name":       event.GetEventName(),
+		"device_id":  event.GetDeviceID(),
+		"account_id": event.GetAccountID(),
+		"trip_id":    event.GetTripID(),
+		"created_at": event.GetCreatedAt(),
+		"data":       group(event),
+	}
+
+	if coords := event.GetCoordinates(); coords != nil {
+		f["location"] = map[string]interface{}{
+			"latitude":  coords.Latitude,
+			"longitude": coords.Longitude,
+			"altitude":  coords.Altitude,
+			"speed":     coords.Speed,
+		}
+	}
+
+	if event.GetCategory() == "EVENT_CATEGORY_DMS" {
+		wrapped := dms.New(event)
+		detection := map[string]interface{}{"attributes": map[string]interface{}{}}
+		if confidence, ok := wrapped.GetConfidence(); ok {
+			detection["confidence"] = confidence
+		}
+		if attributes, ok := wrapped.GetDetection()["attributes"].(map[string]interface{}); ok {
+			detection["attributes"] = attributes
+		}
+		f["dms"] = detection
+	}
+
+	wrapped := telemetry.New(event)
+	if data := wrapped.GetTelemetryData(); data != nil {
+		t := map[string]interface{}{"ignition": string(data.Status)}
+		if ignition := wrapped.GetIgnitionStatus(); ignition != "" {
+			t["ignition"] = string(ignition)
+		}
+		if odometer, ok := wrapped.GetOdometer(); ok {
+			t["odometer"] = odometer
+		}
+		for _, metric := range data.Metrics {
+			switch {
+			case metric == nil:
+			case metric.Component == "BATTERY_COMPONENT_DEVICE":
+				t["device_battery_voltage"] = metric.Voltage
+			case metric.Component == "BATTERY_COMPONENT_VEHICLE":
+				t["vehicle_battery_voltage"] = metric.Voltage
+			}
+		}
+		f["telemetry"] = t
+	}
+
+	return f
+}
+
+func group(event *base.BaseEvent) map[string]interface{} {
+	if g := event.GetEventGroup(); g != nil {
+		return g
+	}
+	return map[string]interface{}{}
+}
@@ -0,0 +1,217 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"gopkg.in/yaml.v3"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/types/base"
+)
+
+var (
+	ErrInvalidRule   = errors.New("rules: invalid rule")
+	ErrUnknownAction = errors.New("rules: unknown action")
+)
+
+type Rule struct {
+	ID          string   `yaml:"id"`
+	Description string   `yaml:"description,omitempty"`
+	Expression  string   `yaml:"expression"`
+	Actions     []string `yaml:"actions,omitempty"`
+	Disabled    bool     `yaml:"disabled,omitempty"`
+}
+
+type Match struct {
+	Rule  *Rule
+	Event *base.BaseEvent
+}
+
+func (m Match) RuleID() string { return m.Rule.ID }
+
+type Action func(ctx context.Context, match Match) error
+
+type Config struct {
+	Actions map[string]Action
+	OnError func(rule *Rule, err error)
+}
+
+// Load reads rules from a YAML document with a top-level `rules` list.
+func Load(r io.Reader) ([]Rule, error) {
+	var doc struct {
+		Rules []Rule `yaml:"rules"`
+	}
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+	}
+	return doc.Rules, nil
+}
+
+func LoadFile(name string) ([]Rule, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rules, err := Load(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return rules, nil
+}
+
+type compiled struct {
+	rule    *Rule
+	program cel.Program
+}
+
+type Engine struct {
+	cfg Config
+	env *cel.Env
+
+	mu    sync.RWMutex
+	rules []compiled
+}
+
+func NewEngine(rules []Rule, cfg Config) (*Engine, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("event", cel.MapType(cel.StringType, cel.DynType)),
+		cel.CrossTypeNumericComparisons(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Engine{cfg: cfg, env: env}
+	if err := e.SetRules(rules); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// SetRules compiles and swaps the rule set. On error the previous rules
+// stay active.
+func (e *Engine) SetRules(rules []Rule) error {
+	programs := make([]compiled, 0, len(rules))
+	seen := make(map[string]bool, len(rules))
+
+	for i := range rules {
+		rule := rules[i]
+		switch {
+		case rule.ID == "":
+			return fmt.Errorf("%w: rule %d has no id", ErrInvalidRule, i)
+		case seen[rule.ID]:
+			return fmt.Errorf("%w: duplicate id %q", ErrInvalidRule, rule.ID)
+		}
+		seen[rule.ID] = true
+
+		for _, action := range rule.Actions {
+			if _, ok := e.cfg.Actions[action]; !ok {
+				return fmt.Errorf("%w: %q in rule %q", ErrUnknownAction, action, rule.ID)
+			}
+		}
+		if rule.Disabled {
+			continue
+		}
+
+		ast, issues := e.env.Compile(rule.Expression)
+		if issues != nil && issues.Err() != nil {
+			return fmt.Errorf("%w: %q: %v", ErrInvalidRule, rule.ID, issues.Err())
+		}
+		if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+			return fmt.Errorf("%w: %q: expression returns %s, want bool", ErrInvalidRule, rule.ID, ast.OutputType())
+		}
+
+		program, err := e.env.Program(ast)
+		if err != nil {
+			return fmt.Errorf("%w: %q: %v", ErrInvalidRule, rule.ID, err)
+		}
+		programs = append(programs, compiled{rule: &rule, program: program})
+	}
+
+	e.mu.Lock()
+	e.rules = programs
+	e.mu.Unlock()
+	return nil
+}
+
+func (e *Engine) Rules() []Rule {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	rules := make([]Rule, len(e.rules))
+	for i, c := range e.rules {
+		rules[i] = *c.rule
+	}
+	return rules
+}
+
+// Evaluate returns the rules matching event, in rule order. A rule whose
+// expression fails on this event does not match and the error goes to
+// Config.OnError; guard optional fields with has() to avoid them.
+func (e *Engine) Evaluate(event *base.BaseEvent) []Match {
+	e.mu.RLock()
+	rules := e.rules
+	e.mu.RUnlock()
+
+	if len(rules) == 0 {
+		return nil
+	}
+
+	activation := map[string]interface{}{"event": fields(event)}
+
+	var matches []Match
+	for _, c := range rules {
+		out, _, err := c.program.Eval(activation)
+		if err != nil {
+			if e.cfg.OnError != nil {
+				e.cfg.OnError(c.rule, err)
+			}
+			continue
+		}
+		if matched, ok := out.Value().(bool); ok && matched {
+			matches = append(matches, Match{Rule: c.rule, Event: event})
+		}
+	}
+	return matches
+}
+
+// Process evaluates event and invokes the actions of every matching rule.
+func (e *Engine) Process(ctx context.Context, event *base.BaseEvent) ([]Match, error) {
+	matches := e.Evaluate(event)
+
+	var errs []error
+	for _, match := range matches {
+		for _, name := range match.Rule.Actions {
+			if err := e.cfg.Actions[name](ctx, match); err != nil {
+				errs = append(errs, fmt.Errorf("rules: action %q for rule %q: %w", name, match.Rule.ID, err))
+			}
+		}
+	}
+	return matches, errors.Join(errs...)
+}
+
+// MatchRules implements webhook.RuleMatcher: it processes event and returns
+// the IDs of the matching rules.
+func (e *Engine) MatchRules(ctx context.Context, event *base.BaseEvent) ([]string, error) {
+	matches, err := e.Process(ctx, event)
+	ids := make([]string, len(matches))
+	for i, match := range matches {
+		ids[i] = match.RuleID()
+	}
+	return ids, err
+}
+
+func (e *Engine) Handler() dispatch.Handler {
+	return func(ctx context.Context, event *base.BaseEvent) error {
+		_, err := e.Process(ctx, event)
+		return err
+	}
+}
@@ -0,0 +1,168 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go-eventlib/pkg/eventio"
+	"go-eventlib/pkg/types/base"
+)
+
+const ruleSet = `
+rules:
+  - id: drowsy-at-speed
+    description: High-confidence drowsiness while moving
+    expression: event.category == "DMS" && event.dms.confidence > 0.85 && has(event.location) && event.location.speed > 40
+    actions: [notify]
+  - id: low-perclos
+    expression: event.category == "DMS" && double(event.dms.attributes.perclos) >= 0.2
+  - id: vehicle-battery-low
+    expression: has(event.telemetry.vehicle_battery_voltage) && event.telemetry.vehicle_battery_voltage < 11.5
+    actions: [notify, page]
+  - id: disabled
+    expression: "true"
+    disabled: true
+`
+
+func fixture(t *testing.T, name string) *base.BaseEvent {
+	t.Helper()
+
+	for event, err := range eventio.ReadFile("../../test/events/" + name + ".json") {
+		if err != nil {
+			t.Fatalf("ReadFile(%s) erro inesperado: %v", name, err)
+		}
+		return event
+	}
+	t.Fatalf("ReadFile(%s) não retornou eventos", name)
+	return nil
+}
+
+func withSpeed(event *base.BaseEvent, speed float64) *base.BaseEvent {
+	group := event.Attributes.Data.TripEvent.(map[string]interface{})["dms"].(map[string]interface{})
+	detection := group["drowsiness"].(map[string]interface{})
+	location := detection["location"].(map[string]interface{})
+	location["coordinates"] = map[string]interface{}{"latitude": -23.5, "longitude": -46.6, "speed": speed}
+	return event
+}
+
+func newEngine(t *testing.T, actions map[string]Action) *Engine {
+	t.Helper()
+
+	rules, err := Load(strings.NewReader(ruleSet))
+	if err != nil {
+		t.Fatalf("Load() erro inesperado: %v", err)
+	}
+	engine, err := NewEngine(rules, Config{Actions: actions})
+	if err != nil {
+		t.Fatalf("NewEngine() erro inesperado: %v", err)
+	}
+	return engine
+}
+
+func noop(ctx context.Context, match Match) error { return nil }
+
+func TestEngine_EvaluatesTypedFields(t *testing.T) {
+	engine := newEngine(t, map[string]Action{"notify": noop, "page": noop})
+	if got := len(engine.Rules()); got != 3 {
+		t.Fatalf("len(Rules()) = %d, esperava 3 regras ativas", got)
+	}
+
+	matches := engine.Evaluate(withSpeed(fixture(t, "dms-events/vision-drowsiness"), 60))
+	if len(matches) != 2 || matches[0].RuleID() != "drowsy-at-speed" || matches[1].RuleID() != "low-perclos" {
+		t.Fatalf("Evaluate() = %+v, esperava drowsy-at-speed e low-perclos", matches)
+	}
+
+	matches = engine.Evaluate(withSpeed(fixture(t, "dms-events/vision-drowsiness"), 20))
+	if len(matches) != 1 || matches[0].RuleID() != "low-perclos" {
+		t.Errorf("Evaluate() = %+v, esperava apenas low-perclos abaixo de 40", matches)
+	}
+
+	if matches := engine.Evaluate(fixture(t, "telemetry-events/telemetry-vehicle-battery")); len(matches) != 0 {
+		t.Errorf("Evaluate() = %+v, bateria a 12.8V não deveria casar", matches)
+	}
+}
+
+func TestEngine_ProcessInvokesNamedActions(t *testing.T) {
+	var calls []string
+	record := func(name string) Action {
+		return func(ctx context.Context, match Match) error {
+			calls = append(calls, name+":"+match.RuleID())
+			return nil
+		}
+	}
+	engine := newEngine(t, map[string]Action{
+		"notify": record("notify"),
+		"page": func(ctx context.Context, match Match) error {
+			return errors.New("pager offline")
+		},
+	})
+
+	event := fixture(t, "telemetry-events/telemetry-vehicle-battery")
+	event.Attributes.Data.Telemetry.(map[string]interface{})["metrics"].(map[string]interface{})["vehicle_battery"].(map[string]interface{})["voltage"] = 10.9
+
+	matches, err := engine.Process(context.Background(), event)
+	if len(matches) != 1 || matches[0].RuleID() != "vehicle-battery-low" {
+		t.Fatalf("Process() = %+v, esperava vehicle-battery-low", matches)
+	}
+	if err == nil || !strings.Contains(err.Error(), "pager offline") {
+		t.Errorf("Process() erro = %v, esperava erro da ação page", err)
+	}
+	if len(calls) != 1 || calls[0] != "notify:vehicle-battery-low" {
+		t.Errorf("calls = %v", calls)
+	}
+}
+
+func TestEngine_RejectsInvalidRules(t *testing.T) {
+	engine := newEngine(t, map[string]Action{"notify": noop, "page": noop})
+
+	tests := []struct {
+		name  string
+		rules []Rule
+		want  error
+	}{
+		{"sem id", []Rule{{Expression: "true"}}, ErrInvalidRule},
+		{"id duplicado", []Rule{{ID: "a", Expression: "true"}, {ID: "a", Expression: "false"}}, ErrInvalidRule},
+		{"sintaxe", []Rule{{ID: "a", Expression: "event.category =="}}, ErrInvalidRule},
+		{"não booleana", []Rule{{ID: "a", Expression: "1 + 2"}}, ErrInvalidRule},
+		{"ação desconhecida", []Rule{{ID: "a", Expression: "true", Actions: []string{"email"}}}, ErrUnknownAction},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := engine.SetRules(tt.rules); !errors.Is(err, tt.want) {
+				t.Errorf("SetRules() erro = %v, esperava %v", err, tt.want)
+			}
+		})
+	}
+
+	if got := len(engine.Rules()); got != 3 {
+		t.Errorf("len(Rules()) = %d, regras anteriores deveriam continuar ativas", got)
+	}
+}
+
+func TestEngine_ReportsUnguardedMissingFields(t *testing.T) {
+	var failed []string
+	engine, err := NewEngine([]Rule{
+		{ID: "guarded", Expression: `has(event.location) && event.location.speed > 40`},
+		{ID: "unguarded", Expression: `event.location.speed > 40`},
+		{ID: "group", Expression: `event.data.event_name == "DROWSINESS"`},
+	}, Config{OnError: func(rule *Rule, err error) { failed = append(failed, rule.ID) }})
+	if err != nil {
+		t.Fatalf("NewEngine() erro inesperado: %v", err)
+	}
+
+	event := fixture(t, "dms-events/vision-drowsiness")
+	event.Attributes.Data.TripEvent.(map[string]interface{})["alt"] = map[string]interface{}{"event_name": "OTHER"}
+
+	matches := engine.Evaluate(event)
+	if len(matches) != 1 || matches[0].RuleID() != "group" {
+		t.Errorf("Evaluate() = %+v, esperava apenas group pelo event_group_name", matches)
+	}
+
+	failed = nil
+	engine.Evaluate(fixture(t, "hardware-events/hardware-reboot"))
+	if len(failed) != 1 || failed[0] != "unguarded" {
+		t.Errorf("OnError = %v, esperava apenas unguarded sem location", failed)
+	}
+}
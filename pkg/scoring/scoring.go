@@ -0,0 +1,248 @@
+package scoring
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"go-eventlib/pkg/stats"
+	"go-eventlib/pkg/trip"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/dms"
+	"go-eventlib/pkg/types/driverbehavior"
+	"go-eventlib/pkg/types/typed"
+)
+
+type Normalization string
+
+const (
+	PerDistance Normalization = "per_100km"
+	PerTime     Normalization = "per_hour"
+)
+
+// Rule scores one event name. Weight is the severity of the event: the
+// penalty of a detection is Weight × confidence.
+type Rule struct {
+	Weight        float64
+	MinConfidence float64
+}
+
+type Config struct {
+	Rules         map[string]Rule
+	Normalization Normalization
+	MinExposure   float64
+	RepeatWindow  time.Duration
+	RepeatDecay   float64
+	HalfLife      time.Duration
+	DriverID      func(event *base.BaseEvent) string
+}
+
+func DefaultRules() map[string]Rule {
+	return map[string]Rule{
+		"HARSH_BRAKING":          {Weight: 5},
+		"HARSH_ACCELERATION":     {Weight: 4},
+		"HARSH_CORNERING":        {Weight: 4},
+		"MAX_SPEED_EXCEEDED":     {Weight: 6},
+		"PERSISTENT_MAX_SPEED":   {Weight: 8},
+		"START_OVERTAKING":       {Weight: 2},
+		"DROWSINESS":             {Weight: 10, MinConfidence: 0.6},
+		"EYE_CLOSURE":            {Weight: 8, MinConfidence: 0.6},
+		"ON_PHONE":               {Weight: 8, MinConfidence: 0.6},
+		"GAZE_DISTRACTION":       {Weight: 5, MinConfidence: 0.6},
+		"GAZE_FIXATION":          {Weight: 3, MinConfidence: 0.6},
+		"POSE_DISTRACTION_PITCH": {Weight: 4, MinConfidence: 0.6},
+		"POSE_DISTRACTION_YAW":   {Weight: 4, MinConfidence: 0.6},
+		"SMOKING":                {Weight: 3, MinConfidence: 0.6},
+		"YAWNING":                {Weight: 2, MinConfidence: 0.6},
+		"EATING":                 {Weight: 2, MinConfidence: 0.6},
+		"DRINKING":               {Weight: 2, MinConfidence: 0.6},
+	}
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Rules:         DefaultRules(),
+		Normalization: PerDistance,
+		MinExposure:   0.1,
+		RepeatWindow:  time.Minute,
+		RepeatDecay:   0.5,
+		HalfLife:      7 * 24 * time.Hour,
+	}
+}
+
+type Exposure struct {
+	Distance float64
+	Duration time.Duration
+}
+
+type Contribution struct {
+	EventName string
+	Weight    float64
+	Count     int
+	Ignored   int
+	Penalty   float64
+}
+
+type Breakdown struct {
+	TripID   string
+	DriverID string
+	EndedAt  time.Time
+	Score    float64
+	Penalty  float64
+	Exposure float64
+	// Normalization is the unit Exposure is measured in. It is PerTime when
+	// PerDistance was configured but the trip has no distance.
+	Normalization Normalization
+	Contributions []Contribution
+}
+
+type Engine struct {
+	cfg Config
+}
+
+func NewEngine(cfg Config) *Engine {
+	defaults := DefaultConfig()
+	if cfg.Rules == nil {
+		cfg.Rules = defaults.Rules
+	}
+	if cfg.Normalization == "" {
+		cfg.Normalization = defaults.Normalization
+	}
+	if cfg.MinExposure <= 0 {
+		cfg.MinExposure = defaults.MinExposure
+	}
+	if cfg.RepeatDecay <= 0 || cfg.RepeatDecay > 1 {
+		cfg.RepeatDecay = defaults.RepeatDecay
+	}
+	if cfg.HalfLife <= 0 {
+		cfg.HalfLife = defaults.HalfLife
+	}
+	if cfg.DriverID == nil {
+		cfg.DriverID = func(event *base.BaseEvent) string { return event.GetDeviceID() }
+	}
+	return &Engine{cfg: cfg}
+}
+
+func (e *Engine) ScoreTrip(t *trip.Trip) *Breakdown {
+	events := make([]base.Event, len(t.Timeline))
+	for i, event := range t.Timeline {
+		events[i] = typed.Wrap(event)
+	}
+
+	breakdown := e.Score(events, Exposure{
+		Distance: stats.ForTrip(t, stats.Config{}).Distance,
+		Duration: t.Duration(),
+	})
+	breakdown.TripID = t.ID
+	breakdown.EndedAt = t.EndedAt
+	return breakdown
+}
+
+func (e *Engine) Score(events []base.Event, exposure Exposure) *Breakdown {
+	sorted := append([]base.Event(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].GetCreatedAt().Before(sorted[j].GetCreatedAt())
+	})
+
+	breakdown := &Breakdown{}
+	contributions := make(map[string]*Contribution)
+	lastSeen := make(map[string]time.Time)
+	repeats := make(map[string]int)
+
+	for _, event := range sorted {
+		name, confidence, baseEvent, ok := observe(event)
+		if !ok {
+			continue
+		}
+		if breakdown.DriverID == "" {
+			breakdown.DriverID = e.cfg.DriverID(baseEvent)
+		}
+		if breakdown.TripID == "" {
+			breakdown.TripID = baseEvent.GetTripID()
+		}
+
+		rule, ok := e.cfg.Rules[name]
+		if !ok {
+			continue
+		}
+
+		c := contributions[name]
+		if c == nil {
+			c = &Contribution{EventName: name, Weight: rule.Weight}
+			contributions[name] = c
+		}
+		if confidence < rule.MinConfidence {
+			c.Ignored++
+			continue
+		}
+
+		createdAt := event.GetCreatedAt()
+		if last, seen := lastSeen[name]; seen && e.cfg.RepeatWindow > 0 && createdAt.Sub(last) < e.cfg.RepeatWindow {
+			repeats[name]++
+		} else {
+			repeats[name] = 0
+		}
+		lastSeen[name] = createdAt
+
+		c.Count++
+		c.Penalty += rule.Weight * confidence * math.Pow(e.cfg.RepeatDecay, float64(repeats[name]))
+	}
+
+	breakdown.Exposure, breakdown.Normalization = e.exposure(exposure)
+
+	for _, c := range contributions {
+		c.Penalty /= breakdown.Exposure
+		breakdown.Penalty += c.Penalty
+		breakdown.Contributions = append(breakdown.Contributions, *c)
+	}
+	sort.Slice(breakdown.Contributions, func(i, j int) bool {
+		a, b := breakdown.Contributions[i], breakdown.Contributions[j]
+		if a.Penalty != b.Penalty {
+			return a.Penalty > b.Penalty
+		}
+		return a.EventName < b.EventName
+	})
+
+	breakdown.Score = math.Max(0, 100-breakdown.Penalty)
+	return breakdown
+}
+
+func (e *Engine) DriverScore(trips []*Breakdown, now time.Time) float64 {
+	var sum, weights float64
+	for _, b := range trips {
+		age := now.Sub(b.EndedAt)
+		if age < 0 {
+			age = 0
+		}
+		w := math.Pow(0.5, float64(age)/float64(e.cfg.HalfLife))
+		sum += w * b.Score
+		weights += w
+	}
+
+	if weights == 0 {
+		return 100
+	}
+	return sum / weights
+}
+
+func (e *Engine) exposure(exposure Exposure) (float64, Normalization) {
+	if e.cfg.Normalization == PerDistance && exposure.Distance > 0 {
+		return math.Max(exposure.Distance/100000, e.cfg.MinExposure), PerDistance
+	}
+	return math.Max(exposure.Duration.Hours(), e.cfg.MinExposure), PerTime
+}
+
+func observe(event base.Event) (string, float64, *base.BaseEvent, bool) {
+	switch ev := event.(type) {
+	case *dms.Event:
+		confidence, ok := ev.GetConfidence()
+		if !ok {
+			confidence = 1
+		}
+		return ev.GetEventName(), confidence, ev.BaseEvent, true
+	case *driverbehavior.Event:
+		return ev.GetEventName(), 1, ev.BaseEvent, true
+	default:
+		return "", 0, nil, false
+	}
+}
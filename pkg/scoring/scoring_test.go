@@ -0,0 +1,175 @@
+package scoring
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-eventlib/pkg/trip"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/dms"
+	"go-eventlib/pkg/types/driverbehavior"
+	"go-eventlib/pkg/webhook"
+)
+
+var start = time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+
+func dmsEvent(id, name string, offset time.Duration, confidence float64) *dms.Event {
+	return dms.New(&base.BaseEvent{
+		ID:        id,
+		CreatedAt: start.Add(offset),
+		Category:  "EVENT_CATEGORY_DMS",
+		Attributes: base.Attributes{
+			Device: &base.Device{ID: "device-1"},
+			Data: &base.Data{TripEvent: map[string]interface{}{
+				"trip_id": "trip-1",
+				"dms": map[string]interface{}{
+					"event_name": name,
+					"detection":  map[string]interface{}{"confidence": confidence},
+				},
+			}},
+		},
+	})
+}
+
+func behaviorEvent(id, name string, offset time.Duration) *driverbehavior.Event {
+	return driverbehavior.New(&base.BaseEvent{
+		ID:        id,
+		CreatedAt: start.Add(offset),
+		Category:  "EVENT_CATEGORY_DRIVER_BEHAVIOR",
+		Attributes: base.Attributes{
+			Device: &base.Device{ID: "device-1"},
+			Data: &base.Data{TripEvent: map[string]interface{}{
+				"trip_id":         "trip-1",
+				"driver_behavior": map[string]interface{}{"event_name": name},
+			}},
+		},
+	})
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestEngine_ScoreBreakdown(t *testing.T) {
+	engine := NewEngine(Config{RepeatDecay: 0.5, RepeatWindow: time.Minute})
+
+	events := []base.Event{
+		behaviorEvent("b-1", "HARSH_BRAKING", 0),
+		behaviorEvent("b-2", "HARSH_BRAKING", 30*time.Second),
+		dmsEvent("d-1", "DROWSINESS", 10*time.Minute, 0.9),
+		dmsEvent("d-2", "DROWSINESS", 20*time.Minute, 0.4),
+		dmsEvent("d-3", "UNKNOWN", 25*time.Minute, 0.9),
+		&base.BaseEvent{ID: "other", CreatedAt: start},
+	}
+
+	got := engine.Score(events, Exposure{Distance: 50000})
+
+	if got.Normalization != PerDistance || !approx(got.Exposure, 0.5) {
+		t.Errorf("exposição = %f %s, esperava 0.5 %s", got.Exposure, got.Normalization, PerDistance)
+	}
+	if got.TripID != "trip-1" || got.DriverID != "device-1" {
+		t.Errorf("TripID = %s, DriverID = %s", got.TripID, got.DriverID)
+	}
+	if len(got.Contributions) != 2 {
+		t.Fatalf("Contributions = %+v, esperava 2", got.Contributions)
+	}
+
+	drowsiness := got.Contributions[0]
+	if drowsiness.EventName != "DROWSINESS" || drowsiness.Count != 1 || drowsiness.Ignored != 1 || !approx(drowsiness.Penalty, 18) {
+		t.Errorf("DROWSINESS = %+v, esperava 1 contado, 1 ignorado, penalidade 18", drowsiness)
+	}
+
+	braking := got.Contributions[1]
+	if braking.Count != 2 || !approx(braking.Penalty, 15) {
+		t.Errorf("HARSH_BRAKING = %+v, esperava 2 ocorrências com penalidade 15", braking)
+	}
+
+	if !approx(got.Penalty, 33) || !approx(got.Score, 67) {
+		t.Errorf("Penalty = %f, Score = %f, esperava 33 e 67", got.Penalty, got.Score)
+	}
+}
+
+func TestEngine_TimeNormalizationAndFloor(t *testing.T) {
+	engine := NewEngine(Config{Normalization: PerTime, Rules: map[string]Rule{"ON_PHONE": {Weight: 50}}})
+	events := []base.Event{dmsEvent("d-1", "ON_PHONE", 0, 1), dmsEvent("d-2", "ON_PHONE", time.Hour, 1), dmsEvent("d-3", "ON_PHONE", 2*time.Hour, 1)}
+
+	got := engine.Score(events, Exposure{Distance: 100000, Duration: 2 * time.Hour})
+	if !approx(got.Score, 25) {
+		t.Errorf("Score = %f, esperava 25", got.Score)
+	}
+
+	got = engine.Score(events, Exposure{})
+	if got.Score != 0 || !approx(got.Exposure, 0.1) {
+		t.Errorf("Score = %f, Exposure = %f, esperava 0 e 0.1", got.Score, got.Exposure)
+	}
+}
+
+func TestEngine_DistanceFallbackIsLabeledPerTime(t *testing.T) {
+	engine := NewEngine(Config{Rules: map[string]Rule{"ON_PHONE": {Weight: 10}}})
+	events := []base.Event{dmsEvent("d-1", "ON_PHONE", 0, 1)}
+
+	got := engine.Score(events, Exposure{Duration: 2 * time.Hour})
+	if got.Normalization != PerTime || !approx(got.Exposure, 2) || !approx(got.Score, 95) {
+		t.Errorf("exposição = %f %s, Score = %f, esperava 2 %s e 95", got.Exposure, got.Normalization, got.Score, PerTime)
+	}
+
+	got = engine.Score(events, Exposure{Distance: 200000, Duration: 2 * time.Hour})
+	if got.Normalization != PerDistance || !approx(got.Exposure, 2) {
+		t.Errorf("exposição = %f %s, esperava 2 %s", got.Exposure, got.Normalization, PerDistance)
+	}
+}
+
+func TestEngine_DriverScoreDecay(t *testing.T) {
+	engine := NewEngine(Config{HalfLife: 24 * time.Hour})
+	now := start.Add(48 * time.Hour)
+
+	trips := []*Breakdown{
+		{Score: 40, EndedAt: start},
+		{Score: 100, EndedAt: now},
+	}
+
+	if got := engine.DriverScore(trips, now); !approx(got, (0.25*40+100)/1.25) {
+		t.Errorf("DriverScore() = %f, esperava %f", got, (0.25*40+100)/1.25)
+	}
+	if got := engine.DriverScore(nil, now); got != 100 {
+		t.Errorf("DriverScore(nil) = %f, esperava 100", got)
+	}
+}
+
+func TestEngine_ScoreTripFixtures(t *testing.T) {
+	files, err := filepath.Glob("../../test/events/*/*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("nenhum fixture encontrado: %v", err)
+	}
+
+	r := trip.NewReconstructor(trip.Config{})
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("erro ao ler %s: %v", file, err)
+		}
+		event, err := webhook.Decode(data)
+		if err != nil {
+			t.Fatalf("Decode(%s) erro inesperado: %v", file, err)
+		}
+		r.Add(event)
+	}
+
+	engine := NewEngine(DefaultConfig())
+	scored := 0
+	for _, tr := range r.Close() {
+		b := engine.ScoreTrip(tr)
+		if b.Score < 0 || b.Score > 100 {
+			t.Errorf("ScoreTrip(%s) = %f, fora de [0, 100]", tr.ID, b.Score)
+		}
+		if len(b.Contributions) > 0 {
+			scored++
+		}
+	}
+	if scored == 0 {
+		t.Error("nenhuma viagem dos fixtures recebeu penalidades")
+	}
+}
@@ -0,0 +1,147 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go-eventlib/pkg/types/base"
+)
+
+var (
+	ErrClosed = errors.New("sink: closed")
+	ErrFull   = errors.New("sink: too many pending events")
+)
+
+// PartialError is returned by a flush func that wrote part of a batch. Only
+// Failed is put back in the buffer; events missing from it are not retried.
+type PartialError struct {
+	Failed []*base.BaseEvent
+	Err    error
+}
+
+func (e *PartialError) Error() string { return e.Err.Error() }
+func (e *PartialError) Unwrap() error { return e.Err }
+
+// Batcher buffers events and hands them to flush in batches. Flushes run
+// one at a time, outside the buffer lock, under a context owned by the
+// batcher: a canceled request never aborts a batch shared with other
+// callers. A failed batch is put back in front of the buffer and retried
+// on the next flush, so delivery is at least once.
+type Batcher struct {
+	cfg   Config
+	flush func(ctx context.Context, events []*base.BaseEvent) error
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	flushMu sync.Mutex
+
+	mu      sync.Mutex
+	pending []*base.BaseEvent
+	closed  bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewBatcher(cfg Config, flush func(ctx context.Context, events []*base.BaseEvent) error) *Batcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &Batcher{
+		cfg:    cfg.WithDefaults(),
+		flush:  flush,
+		ctx:    ctx,
+		cancel: cancel,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go b.loop()
+	return b
+}
+
+// Add buffers event and flushes once a batch is full. It fails with ErrFull
+// while failed batches keep MaxPending events buffered.
+func (b *Batcher) Add(ctx context.Context, event *base.BaseEvent) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return ErrClosed
+	}
+	if len(b.pending) >= b.cfg.MaxPending {
+		b.mu.Unlock()
+		return ErrFull
+	}
+
+	b.pending = append(b.pending, event)
+	full := len(b.pending) >= b.cfg.BatchSize
+	b.mu.Unlock()
+
+	if !full {
+		return nil
+	}
+	return b.Flush(ctx)
+}
+
+// Flush writes every buffered event. ctx only identifies the caller; the
+// write itself runs under the batcher's context.
+func (b *Batcher) Flush(ctx context.Context) error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	events := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	if err := b.flush(b.ctx, events); err != nil {
+		var partial *PartialError
+		if errors.As(err, &partial) {
+			events = partial.Failed
+		}
+
+		b.mu.Lock()
+		b.pending = append(events, b.pending...)
+		b.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (b *Batcher) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.mu.Unlock()
+
+	close(b.stop)
+	<-b.done
+
+	defer b.cancel()
+	return b.Flush(b.ctx)
+}
+
+func (b *Batcher) loop() {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+			if err := b.Flush(b.ctx); err != nil && b.cfg.OnError != nil {
+				b.cfg.OnError(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package jsonl
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-eventlib/pkg/sink"
+	"go-eventlib/pkg/types/base"
+)
+
+const (
+	defaultMaxFileSize = 64 << 20
+	defaultMaxIdle     = 10 * time.Minute
+	UnknownAccount     = "_unknown"
+	filePrefix         = "events-"
+	fileSuffix         = ".jsonl.gz"
+)
+
+type Config struct {
+	sink.Config
+	Dir         string
+	MaxFileSize int64
+	// MaxIdle closes a partition file that has not been written to for this
+	// long, so files of past days do not stay open. The next event of that
+	// partition starts a new file.
+	MaxIdle time.Duration
+	Now     func() time.Time
+}
+
+type partition struct {
+	day     string
+	account string
+}
+
+type file struct {
+	f         *os.File
+	zw        *gzip.Writer
+	seq       int
+	size      int64
+	lastWrite time.Time
+}
+
+type Archive struct {
+	cfg     Config
+	batcher *sink.Batcher
+
+	mu    sync.Mutex
+	files map[partition]*file
+}
+
+func New(cfg Config) (*Archive, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("jsonl: Dir is required")
+	}
+	if cfg.MaxFileSize <= 0 {
+		cfg.MaxFileSize = defaultMaxFileSize
+	}
+	if cfg.MaxIdle <= 0 {
+		cfg.MaxIdle = defaultMaxIdle
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("jsonl: creating %s: %w", cfg.Dir, err)
+	}
+
+	a := &Archive{
+		cfg:   cfg,
+		files: make(map[partition]*file),
+	}
+	a.batcher = sink.NewBatcher(cfg.Config, a.writeBatch)
+
+	return a, nil
+}
+
+func (a *Archive) Write(ctx context.Context, event *base.BaseEvent) error {
+	return a.batcher.Add(ctx, event)
+}
+
+func (a *Archive) Flush(ctx context.Context) error {
+	if err := a.batcher.Flush(ctx); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	errs := []error{a.closeIdleLocked(a.cfg.Now())}
+	for _, f := range a.files {
+		errs = append(errs, f.zw.Flush())
+	}
+	return errors.Join(errs...)
+}
+
+func (a *Archive) Close() error {
+	err := a.batcher.Close()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	errs := []error{err}
+	for key, f := range a.files {
+		errs = append(errs, f.close())
+		delete(a.files, key)
+	}
+	return errors.Join(errs...)
+}
+
+func PartitionDir(dir string, event *base.BaseEvent) string {
+	p := partitionOf(event)
+	return filepath.Join(dir, p.day, p.account)
+}
+
+func (a *Archive) writeBatch(ctx context.Context, events []*base.BaseEvent) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.cfg.Now()
+	touched := make(map[*file]bool)
+	var failed []*base.BaseEvent
+	var errs []error
+
+	for _, event := range events {
+		line, err := json.Marshal(event)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("jsonl: encoding event %s: %w", event.GetID(), err))
+			continue
+		}
+		line = append(line, '\n')
+
+		f, err := a.fileFor(partitionOf(event), int64(len(line)))
+		if err != nil {
+			failed = append(failed, event)
+			errs = append(errs, err)
+			continue
+		}
+
+		n, err := f.zw.Write(line)
+		f.size += int64(n)
+		if err != nil {
+			failed = append(failed, event)
+			errs = append(errs, fmt.Errorf("jsonl: writing event %s: %w", event.GetID(), err))
+			continue
+		}
+		f.lastWrite = now
+		touched[f] = true
+	}
+
+	for f := range touched {
+		if err := f.zw.Flush(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, a.closeIdleLocked(now))
+
+	// Events that reached a file are not retried, so a failed batch never
+	// writes the same line twice; events that cannot be encoded never will be.
+	if err := errors.Join(errs...); err != nil {
+		return &sink.PartialError{Failed: failed, Err: err}
+	}
+	return nil
+}
+
+// closeIdleLocked closes the files not written to within MaxIdle. Their
+// events are already flushed, so a close error is only reported.
+func (a *Archive) closeIdleLocked(now time.Time) error {
+	var errs []error
+	for p, f := range a.files {
+		if now.Sub(f.lastWrite) >= a.cfg.MaxIdle {
+			delete(a.files, p)
+			errs = append(errs, f.close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (a *Archive) fileFor(p partition, next int64) (*file, error) {
+	f, ok := a.files[p]
+	if ok && f.size+next <= a.cfg.MaxFileSize {
+		return f, nil
+	}
+
+	dir := filepath.Join(a.cfg.Dir, p.day, p.account)
+	seq := 1
+	if ok {
+		seq = f.seq + 1
+		delete(a.files, p)
+		if err := f.close(); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("jsonl: creating %s: %w", dir, err)
+		}
+		seq = nextSeq(dir)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s%06d%s", filePrefix, seq, fileSuffix))
+	osFile, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("jsonl: opening %s: %w", path, err)
+	}
+
+	f = &file{f: osFile, zw: gzip.NewWriter(osFile), seq: seq}
+	a.files[p] = f
+	return f, nil
+}
+
+func (f *file) close() error {
+	return errors.Join(f.zw.Close(), f.f.Close())
+}
+
+func Files(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*", filePrefix+"*"+fileSuffix))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func nextSeq(dir string) int {
+	matches, _ := filepath.Glob(filepath.Join(dir, filePrefix+"*"+fileSuffix))
+
+	seq := 0
+	for _, match := range matches {
+		var n int
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(match), filePrefix), fileSuffix)
+		if _, err := fmt.Sscanf(name, "%d", &n); err == nil && n > seq {
+			seq = n
+		}
+	}
+	return seq + 1
+}
+
+func partitionOf(event *base.BaseEvent) partition {
+	account := event.GetAccountID()
+	if account == "" || strings.ContainsAny(account, `/\.`) {
+		account = UnknownAccount
+	}
+
+	return partition{
+		day:     event.GetCreatedAt().UTC().Format("2006-01-02"),
+		account: account,
+	}
+}
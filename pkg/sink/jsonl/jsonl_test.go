@@ -0,0 +1,210 @@
+package jsonl
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-eventlib/pkg/sink"
+	"go-eventlib/pkg/types/base"
+)
+
+func testEvent(id, account string, createdAt time.Time) *base.BaseEvent {
+	return &base.BaseEvent{
+		ID:        id,
+		CreatedAt: createdAt,
+		Category:  "EVENT_CATEGORY_DMS",
+		Attributes: base.Attributes{
+			Device: &base.Device{ID: "device-1", AccountID: account},
+		},
+	}
+}
+
+func readEvents(t *testing.T, path string) []*base.BaseEvent {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("erro ao abrir %s: %v", path, err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("erro ao descomprimir %s: %v", path, err)
+	}
+
+	var events []*base.BaseEvent
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		var event base.BaseEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("linha inválida em %s: %v", path, err)
+		}
+		events = append(events, &event)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("erro ao ler %s: %v", path, err)
+	}
+	return events
+}
+
+func TestArchive_PartitionsByDayAndAccount(t *testing.T) {
+	dir := t.TempDir()
+	archive, err := New(Config{Dir: dir, Config: sink.Config{FlushInterval: time.Hour}})
+	if err != nil {
+		t.Fatalf("New() erro inesperado: %v", err)
+	}
+
+	day1 := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	events := []*base.BaseEvent{
+		testEvent("event-1", "account-a", day1),
+		testEvent("event-2", "account-a", day1),
+		testEvent("event-3", "account-b", day1),
+		testEvent("event-4", "account-a", day2),
+		testEvent("event-5", "", day2),
+	}
+
+	for _, event := range events {
+		if err := archive.Write(context.Background(), event); err != nil {
+			t.Fatalf("Write() erro inesperado: %v", err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("Close() erro inesperado: %v", err)
+	}
+
+	want := map[string]int{
+		filepath.Join(dir, "2025-03-01", "account-a", "events-000001.jsonl.gz"): 2,
+		filepath.Join(dir, "2025-03-01", "account-b", "events-000001.jsonl.gz"): 1,
+		filepath.Join(dir, "2025-03-02", "account-a", "events-000001.jsonl.gz"): 1,
+		filepath.Join(dir, "2025-03-02", "_unknown", "events-000001.jsonl.gz"):  1,
+	}
+
+	files, err := Files(dir)
+	if err != nil {
+		t.Fatalf("Files() erro inesperado: %v", err)
+	}
+	if len(files) != len(want) {
+		t.Fatalf("Files() = %v, esperava %d arquivos", files, len(want))
+	}
+	for path, count := range want {
+		if got := len(readEvents(t, path)); got != count {
+			t.Errorf("%s tem %d eventos, esperava %d", path, got, count)
+		}
+	}
+}
+
+func TestArchive_RotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	archive, err := New(Config{Dir: dir, MaxFileSize: 1, Config: sink.Config{BatchSize: 1, FlushInterval: time.Hour}})
+	if err != nil {
+		t.Fatalf("New() erro inesperado: %v", err)
+	}
+
+	createdAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	for _, id := range []string{"event-1", "event-2", "event-3"} {
+		if err := archive.Write(context.Background(), testEvent(id, "account-a", createdAt)); err != nil {
+			t.Fatalf("Write() erro inesperado: %v", err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("Close() erro inesperado: %v", err)
+	}
+
+	files, err := Files(dir)
+	if err != nil {
+		t.Fatalf("Files() erro inesperado: %v", err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("Files() = %v, esperava 3 arquivos", files)
+	}
+	if got := readEvents(t, files[2]); len(got) != 1 || got[0].ID != "event-3" {
+		t.Errorf("último arquivo = %+v, esperava event-3", got)
+	}
+}
+
+func TestArchive_ContinuesExistingSequence(t *testing.T) {
+	dir := t.TempDir()
+	createdAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, id := range []string{"event-1", "event-2"} {
+		archive, err := New(Config{Dir: dir})
+		if err != nil {
+			t.Fatalf("New() erro inesperado: %v", err)
+		}
+		if err := archive.Write(context.Background(), testEvent(id, "account-a", createdAt)); err != nil {
+			t.Fatalf("Write() erro inesperado: %v", err)
+		}
+		if err := archive.Close(); err != nil {
+			t.Fatalf("Close() erro inesperado: %v", err)
+		}
+	}
+
+	files, err := Files(dir)
+	if err != nil {
+		t.Fatalf("Files() erro inesperado: %v", err)
+	}
+	if len(files) != 2 || filepath.Base(files[1]) != "events-000002.jsonl.gz" {
+		t.Errorf("Files() = %v, esperava events-000001 e events-000002", files)
+	}
+}
+
+func TestArchive_ClosesIdlePartitions(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 3, 2, 0, 5, 0, 0, time.UTC)
+	archive, err := New(Config{
+		Dir:     dir,
+		MaxIdle: time.Minute,
+		Now:     func() time.Time { return now },
+		Config:  sink.Config{BatchSize: 1, FlushInterval: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("New() erro inesperado: %v", err)
+	}
+	defer archive.Close()
+
+	ctx := context.Background()
+	yesterday := time.Date(2025, 3, 1, 23, 59, 0, 0, time.UTC)
+	if err := archive.Write(ctx, testEvent("event-1", "account-a", yesterday)); err != nil {
+		t.Fatalf("Write() erro inesperado: %v", err)
+	}
+	now = now.Add(2 * time.Minute)
+	if err := archive.Write(ctx, testEvent("event-2", "account-a", now)); err != nil {
+		t.Fatalf("Write() erro inesperado: %v", err)
+	}
+
+	archive.mu.Lock()
+	open := len(archive.files)
+	archive.mu.Unlock()
+	if open != 1 {
+		t.Fatalf("%d arquivos abertos, esperava apenas a partição de hoje", open)
+	}
+
+	files, err := Files(dir)
+	if err != nil {
+		t.Fatalf("Files() erro inesperado: %v", err)
+	}
+	if got := readEvents(t, files[0]); len(got) != 1 || got[0].ID != "event-1" {
+		t.Errorf("arquivo de ontem = %+v, esperava event-1 completo", got)
+	}
+}
+
+func TestArchive_WriteAfterClose(t *testing.T) {
+	archive, err := New(Config{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("New() erro inesperado: %v", err)
+	}
+	archive.Close()
+
+	err = archive.Write(context.Background(), testEvent("event-1", "account-a", time.Now()))
+	if err != sink.ErrClosed {
+		t.Errorf("Write() = %v, esperava sink.ErrClosed", err)
+	}
+}
@@ -0,0 +1,69 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/types/base"
+)
+
+type EventSink interface {
+	Write(ctx context.Context, event *base.BaseEvent) error
+	Flush(ctx context.Context) error
+	Close() error
+}
+
+type Config struct {
+	BatchSize     int
+	FlushInterval time.Duration
+	// MaxPending caps the events kept in memory while flushes fail.
+	MaxPending int
+	OnError    func(err error)
+}
+
+func (c Config) WithDefaults() Config {
+	if c.BatchSize <= 0 {
+		c.BatchSize = 100
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = 5 * time.Second
+	}
+	if c.MaxPending < c.BatchSize {
+		c.MaxPending = 10 * c.BatchSize
+	}
+	return c
+}
+
+func Handler(s EventSink) dispatch.Handler {
+	return func(ctx context.Context, event *base.BaseEvent) error {
+		return s.Write(ctx, event)
+	}
+}
+
+type Multi []EventSink
+
+func (m Multi) Write(ctx context.Context, event *base.BaseEvent) error {
+	var errs []error
+	for _, s := range m {
+		errs = append(errs, s.Write(ctx, event))
+	}
+	return errors.Join(errs...)
+}
+
+func (m Multi) Flush(ctx context.Context) error {
+	var errs []error
+	for _, s := range m {
+		errs = append(errs, s.Flush(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+func (m Multi) Close() error {
+	var errs []error
+	for _, s := range m {
+		errs = append(errs, s.Close())
+	}
+	return errors.Join(errs...)
+}
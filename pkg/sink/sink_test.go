@@ -0,0 +1,222 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go-eventlib/pkg/types/base"
+)
+
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]*base.BaseEvent
+	err     error
+}
+
+func (r *recorder) flush(ctx context.Context, events []*base.BaseEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.batches = append(r.batches, events)
+	return r.err
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.batches)
+}
+
+func TestBatcher_FlushesWhenFull(t *testing.T) {
+	rec := &recorder{}
+	b := NewBatcher(Config{BatchSize: 2, FlushInterval: time.Hour}, rec.flush)
+	defer b.Close()
+
+	for _, id := range []string{"event-1", "event-2", "event-3"} {
+		if err := b.Add(context.Background(), &base.BaseEvent{ID: id}); err != nil {
+			t.Fatalf("Add() erro inesperado: %v", err)
+		}
+	}
+
+	if rec.count() != 1 || len(rec.batches[0]) != 2 {
+		t.Errorf("lotes = %v, esperava um lote com 2 eventos", rec.batches)
+	}
+}
+
+func TestBatcher_FlushesOnClose(t *testing.T) {
+	rec := &recorder{}
+	b := NewBatcher(Config{FlushInterval: time.Hour}, rec.flush)
+
+	b.Add(context.Background(), &base.BaseEvent{ID: "event-1"})
+	if err := b.Close(); err != nil {
+		t.Fatalf("Close() erro inesperado: %v", err)
+	}
+
+	if rec.count() != 1 {
+		t.Errorf("lotes = %d, esperava 1", rec.count())
+	}
+	if err := b.Add(context.Background(), &base.BaseEvent{ID: "event-2"}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Add() após Close = %v, esperava ErrClosed", err)
+	}
+}
+
+func TestBatcher_ReportsIntervalFlushErrors(t *testing.T) {
+	flushErr := errors.New("disco cheio")
+	rec := &recorder{err: flushErr}
+	reported := make(chan error, 1)
+
+	b := NewBatcher(Config{
+		FlushInterval: 5 * time.Millisecond,
+		OnError: func(err error) {
+			select {
+			case reported <- err:
+			default:
+			}
+		},
+	}, rec.flush)
+	defer b.Close()
+
+	b.Add(context.Background(), &base.BaseEvent{ID: "event-1"})
+
+	select {
+	case err := <-reported:
+		if !errors.Is(err, flushErr) {
+			t.Errorf("OnError recebeu %v, esperava %v", err, flushErr)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnError não foi chamado")
+	}
+}
+
+func TestBatcher_RequeuesFailedBatch(t *testing.T) {
+	rec := &recorder{err: errors.New("disco cheio")}
+	b := NewBatcher(Config{BatchSize: 2, MaxPending: 3, FlushInterval: time.Hour}, rec.flush)
+	defer b.Close()
+
+	b.Add(context.Background(), &base.BaseEvent{ID: "event-1"})
+	if err := b.Add(context.Background(), &base.BaseEvent{ID: "event-2"}); err == nil {
+		t.Fatal("Add() deveria retornar o erro do flush")
+	}
+	b.Add(context.Background(), &base.BaseEvent{ID: "event-3"})
+	if err := b.Add(context.Background(), &base.BaseEvent{ID: "event-4"}); !errors.Is(err, ErrFull) {
+		t.Errorf("Add() com buffer cheio = %v, esperava ErrFull", err)
+	}
+
+	rec.mu.Lock()
+	rec.err = nil
+	rec.mu.Unlock()
+	if err := b.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() erro inesperado: %v", err)
+	}
+
+	last := rec.batches[len(rec.batches)-1]
+	if len(last) != 3 || last[0].ID != "event-1" || last[2].ID != "event-3" {
+		t.Errorf("lote reenviado = %v, esperava event-1..event-3 em ordem", last)
+	}
+}
+
+func TestBatcher_RequeuesOnlyFailedEvents(t *testing.T) {
+	var batches [][]string
+	b := NewBatcher(Config{FlushInterval: time.Hour}, func(ctx context.Context, events []*base.BaseEvent) error {
+		var ids []string
+		for _, event := range events {
+			ids = append(ids, event.ID)
+		}
+		batches = append(batches, ids)
+		if len(batches) == 1 {
+			return &PartialError{Failed: events[1:], Err: errors.New("falha parcial")}
+		}
+		return nil
+	})
+	defer b.Close()
+
+	b.Add(context.Background(), &base.BaseEvent{ID: "event-1"})
+	b.Add(context.Background(), &base.BaseEvent{ID: "event-2"})
+	if err := b.Flush(context.Background()); err == nil {
+		t.Fatal("Flush() deveria retornar a falha parcial")
+	}
+	b.Flush(context.Background())
+
+	if len(batches) != 2 || len(batches[1]) != 1 || batches[1][0] != "event-2" {
+		t.Errorf("lotes = %v, esperava reenviar só event-2", batches)
+	}
+}
+
+func TestBatcher_FlushIgnoresCallerCancellation(t *testing.T) {
+	var flushCtx context.Context
+	b := NewBatcher(Config{FlushInterval: time.Hour}, func(ctx context.Context, events []*base.BaseEvent) error {
+		flushCtx = ctx
+		return ctx.Err()
+	})
+	defer b.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	b.Add(ctx, &base.BaseEvent{ID: "event-1"})
+	if err := b.Flush(ctx); err != nil {
+		t.Errorf("Flush() com contexto cancelado = %v, esperava sucesso", err)
+	}
+	if flushCtx == ctx {
+		t.Error("flush recebeu o contexto do chamador")
+	}
+}
+
+func TestBatcher_AddDoesNotWaitForFlush(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	b := NewBatcher(Config{BatchSize: 2, FlushInterval: time.Hour}, func(ctx context.Context, events []*base.BaseEvent) error {
+		if events[0].ID == "event-1" {
+			close(started)
+			<-release
+		}
+		return nil
+	})
+	defer b.Close()
+	defer close(release)
+
+	b.Add(context.Background(), &base.BaseEvent{ID: "event-1"})
+	go b.Add(context.Background(), &base.BaseEvent{ID: "event-2"})
+	<-started
+
+	added := make(chan error)
+	go func() { added <- b.Add(context.Background(), &base.BaseEvent{ID: "event-3"}) }()
+
+	select {
+	case err := <-added:
+		if err != nil {
+			t.Errorf("Add() erro inesperado: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("Add() ficou bloqueado durante o flush")
+	}
+}
+
+func TestMulti_JoinsErrors(t *testing.T) {
+	failing := errors.New("falha")
+	m := Multi{&stubSink{}, &stubSink{err: failing}}
+
+	if err := m.Write(context.Background(), &base.BaseEvent{ID: "event-1"}); !errors.Is(err, failing) {
+		t.Errorf("Write() = %v, esperava %v", err, failing)
+	}
+	if m[0].(*stubSink).writes != 1 {
+		t.Error("primeiro sink não recebeu o evento")
+	}
+}
+
+type stubSink struct {
+	writes int
+	err    error
+}
+
+func (s *stubSink) Write(ctx context.Context, event *base.BaseEvent) error {
+	s.writes++
+	return s.err
+}
+
+func (s *stubSink) Flush(ctx context.Context) error { return nil }
+func (s *stubSink) Close() error                    { return nil }
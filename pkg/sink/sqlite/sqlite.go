@@ -0,0 +1,239 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	_ "modernc.org/sqlite"
+
+	"go-eventlib/pkg/sink"
+	"go-eventlib/pkg/types/base"
+)
+
+const registryTable = "event_tables"
+
+type Config struct {
+	sink.Config
+	Path string
+}
+
+type Sink struct {
+	db      *sql.DB
+	ownsDB  bool
+	batcher *sink.Batcher
+
+	mu     sync.Mutex
+	tables map[string]bool
+}
+
+func Open(cfg Config) (*Sink, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("sqlite: Path is required")
+	}
+
+	db, err := sql.Open("sqlite", cfg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: opening %s: %w", cfg.Path, err)
+	}
+	db.SetMaxOpenConns(1)
+
+	for _, pragma := range []string{"PRAGMA journal_mode=WAL", "PRAGMA busy_timeout=5000"} {
+		if _, err := db.Exec(pragma); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("sqlite: %s: %w", pragma, err)
+		}
+	}
+
+	s, err := New(db, cfg.Config)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	s.ownsDB = true
+	return s, nil
+}
+
+func New(db *sql.DB, cfg sink.Config) (*Sink, error) {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS ` + registryTable + ` (name TEXT PRIMARY KEY, category TEXT NOT NULL)`)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: creating %s: %w", registryTable, err)
+	}
+
+	s := &Sink{
+		db:     db,
+		tables: make(map[string]bool),
+	}
+	s.batcher = sink.NewBatcher(cfg, s.writeBatch)
+
+	return s, nil
+}
+
+func (s *Sink) DB() *sql.DB {
+	return s.db
+}
+
+func (s *Sink) Write(ctx context.Context, event *base.BaseEvent) error {
+	return s.batcher.Add(ctx, event)
+}
+
+func (s *Sink) Flush(ctx context.Context) error {
+	return s.batcher.Flush(ctx)
+}
+
+func (s *Sink) Close() error {
+	err := s.batcher.Close()
+	if s.ownsDB {
+		err = errors.Join(err, s.db.Close())
+	}
+	return err
+}
+
+func TableName(category base.EventCategory) string {
+	name := strings.ToLower(strings.TrimPrefix(string(category), "EVENT_CATEGORY_"))
+
+	var b strings.Builder
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return "events_unknown"
+	}
+	return "events_" + b.String()
+}
+
+func Tables(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `SELECT name FROM `+registryTable+` ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: listing tables: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("sqlite: listing tables: %w", err)
+		}
+		tables = append(tables, name)
+	}
+	return tables, rows.Err()
+}
+
+func (s *Sink) writeBatch(ctx context.Context, events []*base.BaseEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("sqlite: begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	created := make(map[string]bool)
+	var skipped []error
+	for _, event := range events {
+		table := TableName(event.GetCategory())
+		if !s.tables[table] && !created[table] {
+			if err := createTable(ctx, tx, table, event.GetCategory()); err != nil {
+				return err
+			}
+			created[table] = true
+		}
+
+		// Each event runs in its own savepoint, so one row that cannot be
+		// inserted is skipped instead of failing the batch on every retry.
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT event`); err != nil {
+			return fmt.Errorf("sqlite: savepoint: %w", err)
+		}
+		if err := insert(ctx, tx, table, event); err != nil {
+			if _, rbErr := tx.ExecContext(ctx, `ROLLBACK TO event`); rbErr != nil {
+				return fmt.Errorf("sqlite: rollback to savepoint: %w", rbErr)
+			}
+			skipped = append(skipped, err)
+		}
+		if _, err := tx.ExecContext(ctx, `RELEASE event`); err != nil {
+			return fmt.Errorf("sqlite: release savepoint: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("sqlite: commit: %w", err)
+	}
+
+	for table := range created {
+		s.tables[table] = true
+	}
+	if len(skipped) > 0 {
+		return &sink.PartialError{Err: errors.Join(skipped...)}
+	}
+	return nil
+}
+
+func createTable(ctx context.Context, tx *sql.Tx, table string, category base.EventCategory) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS ` + table + ` (
+			id TEXT PRIMARY KEY,
+			device_id TEXT NOT NULL,
+			account_id TEXT NOT NULL,
+			trip_id TEXT NOT NULL,
+			category TEXT NOT NULL,
+			sub TEXT NOT NULL,
+			event_name TEXT NOT NULL,
+			status TEXT NOT NULL,
+			type TEXT NOT NULL,
+			created_at INTEGER NOT NULL,
+			payload TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_` + table + `_device_id ON ` + table + ` (device_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_` + table + `_account_id ON ` + table + ` (account_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_` + table + `_trip_id ON ` + table + ` (trip_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_` + table + `_event_name ON ` + table + ` (event_name, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_` + table + `_created_at ON ` + table + ` (created_at, id)`,
+	}
+
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("sqlite: creating table %s: %w", table, err)
+		}
+	}
+
+	_, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO `+registryTable+` (name, category) VALUES (?, ?)`, table, string(category))
+	if err != nil {
+		return fmt.Errorf("sqlite: registering table %s: %w", table, err)
+	}
+	return nil
+}
+
+func insert(ctx context.Context, tx *sql.Tx, table string, event *base.BaseEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("sqlite: encoding event %s: %w", event.GetID(), err)
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT OR REPLACE INTO `+table+`
+		(id, device_id, account_id, trip_id, category, sub, event_name, status, type, created_at, payload)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.GetID(),
+		event.GetDeviceID(),
+		event.GetAccountID(),
+		event.GetTripID(),
+		string(event.GetCategory()),
+		string(event.GetSubType()),
+		event.GetEventName(),
+		string(event.Status),
+		string(event.Type),
+		event.GetCreatedAt().UnixNano(),
+		string(payload),
+	)
+	if err != nil {
+		return fmt.Errorf("sqlite: inserting event %s: %w", event.GetID(), err)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package sqlite
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-eventlib/pkg/sink"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/webhook"
+)
+
+func loadFixtures(t *testing.T) []*base.BaseEvent {
+	t.Helper()
+
+	files, err := filepath.Glob("../../../test/events/*/*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("nenhum fixture encontrado: %v", err)
+	}
+
+	var events []*base.BaseEvent
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("erro ao ler %s: %v", file, err)
+		}
+		event, err := webhook.Decode(data)
+		if err != nil {
+			t.Fatalf("Decode(%s) erro inesperado: %v", file, err)
+		}
+		// Several fixtures share the same id; the sink upserts by id.
+		event.ID = strings.TrimSuffix(filepath.Base(file), ".json")
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestTableName(t *testing.T) {
+	tests := []struct {
+		category base.EventCategory
+		want     string
+	}{
+		{"EVENT_CATEGORY_DMS", "events_dms"},
+		{"EVENT_CATEGORY_DRIVER_BEHAVIOR", "events_driver_behavior"},
+		{"EVENT_CATEGORY_HEALTH; DROP TABLE x", "events_healthdroptablex"},
+		{"", "events_unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := TableName(tt.category); got != tt.want {
+			t.Errorf("TableName(%q) = %s, esperava %s", tt.category, got, tt.want)
+		}
+	}
+}
+
+func TestSink_WritesFixturesPerCategory(t *testing.T) {
+	s, err := Open(Config{Path: filepath.Join(t.TempDir(), "events.db"), Config: sink.Config{FlushInterval: time.Hour}})
+	if err != nil {
+		t.Fatalf("Open() erro inesperado: %v", err)
+	}
+	defer s.Close()
+
+	events := loadFixtures(t)
+	want := make(map[string]int)
+	for _, event := range events {
+		if err := s.Write(context.Background(), event); err != nil {
+			t.Fatalf("Write() erro inesperado: %v", err)
+		}
+		want[TableName(event.GetCategory())]++
+	}
+	if err := s.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() erro inesperado: %v", err)
+	}
+
+	for table, count := range want {
+		var got int
+		if err := s.DB().QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&got); err != nil {
+			t.Fatalf("erro ao contar %s: %v", table, err)
+		}
+		if got != count {
+			t.Errorf("%s tem %d linhas, esperava %d", table, got, count)
+		}
+	}
+
+	var eventName string
+	err = s.DB().QueryRow(`SELECT event_name FROM events_dms WHERE event_name = ?`, "DROWSINESS").Scan(&eventName)
+	if err != nil {
+		t.Errorf("evento DROWSINESS não encontrado em events_dms: %v", err)
+	}
+}
+
+func TestSink_CreatesIndexes(t *testing.T) {
+	s, err := Open(Config{Path: filepath.Join(t.TempDir(), "events.db")})
+	if err != nil {
+		t.Fatalf("Open() erro inesperado: %v", err)
+	}
+	defer s.Close()
+
+	event := &base.BaseEvent{ID: "event-1", Category: "EVENT_CATEGORY_DMS", CreatedAt: time.Now()}
+	if err := s.Write(context.Background(), event); err != nil {
+		t.Fatalf("Write() erro inesperado: %v", err)
+	}
+	if err := s.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() erro inesperado: %v", err)
+	}
+
+	for _, index := range []string{"device_id", "trip_id", "event_name", "created_at"} {
+		name := "idx_events_dms_" + index
+		var got string
+		err := s.DB().QueryRow(`SELECT name FROM sqlite_master WHERE type = 'index' AND name = ?`, name).Scan(&got)
+		if err != nil {
+			t.Errorf("índice %s não encontrado: %v", name, err)
+		}
+	}
+}
+
+func TestSink_FlushesOnClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.db")
+	s, err := Open(Config{Path: path, Config: sink.Config{FlushInterval: time.Hour}})
+	if err != nil {
+		t.Fatalf("Open() erro inesperado: %v", err)
+	}
+
+	event := &base.BaseEvent{ID: "event-1", Category: "EVENT_CATEGORY_TELEMETRY", CreatedAt: time.Now()}
+	if err := s.Write(context.Background(), event); err != nil {
+		t.Fatalf("Write() erro inesperado: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() erro inesperado: %v", err)
+	}
+
+	reopened, err := Open(Config{Path: path})
+	if err != nil {
+		t.Fatalf("Open() erro inesperado: %v", err)
+	}
+	defer reopened.Close()
+
+	var count int
+	if err := reopened.DB().QueryRow(`SELECT COUNT(*) FROM events_telemetry`).Scan(&count); err != nil {
+		t.Fatalf("erro ao contar events_telemetry: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("events_telemetry tem %d linhas, esperava 1", count)
+	}
+}
+
+func TestSink_SkipsEventsThatCannotBeInserted(t *testing.T) {
+	s, err := Open(Config{Path: filepath.Join(t.TempDir(), "events.db"), Config: sink.Config{FlushInterval: time.Hour}})
+	if err != nil {
+		t.Fatalf("Open() erro inesperado: %v", err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+	telemetry := func(id string) *base.BaseEvent {
+		return &base.BaseEvent{ID: id, Category: "EVENT_CATEGORY_TELEMETRY", CreatedAt: time.Now()}
+	}
+	s.Write(ctx, telemetry("event-1"))
+	if err := s.Flush(ctx); err != nil {
+		t.Fatalf("Flush() erro inesperado: %v", err)
+	}
+	_, err = s.DB().Exec(`CREATE TRIGGER reject BEFORE INSERT ON events_telemetry
+		WHEN NEW.id = 'bad' BEGIN SELECT RAISE(ABORT, 'rejeitado'); END`)
+	if err != nil {
+		t.Fatalf("erro ao criar trigger: %v", err)
+	}
+
+	s.Write(ctx, telemetry("bad"))
+	s.Write(ctx, telemetry("event-2"))
+	if err := s.Flush(ctx); err == nil || !strings.Contains(err.Error(), "bad") {
+		t.Errorf("Flush() = %v, esperava erro do evento rejeitado", err)
+	}
+	if err := s.Flush(ctx); err != nil {
+		t.Errorf("Flush() = %v, evento rejeitado não deveria ser retentado", err)
+	}
+
+	var count int
+	if err := s.DB().QueryRow(`SELECT COUNT(*) FROM events_telemetry`).Scan(&count); err != nil {
+		t.Fatalf("erro ao contar events_telemetry: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("events_telemetry tem %d linhas, esperava 2", count)
+	}
+}
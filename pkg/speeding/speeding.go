@@ -0,0 +1,212 @@
+package speeding
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/stats"
+	"go-eventlib/pkg/trip"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/driverbehavior"
+)
+
+const (
+	EventMaxSpeedExceeded    = "MAX_SPEED_EXCEEDED"
+	EventPersistentMaxSpeed  = "PERSISTENT_MAX_SPEED"
+	EventReturnToNormalSpeed = "RETURN_TO_NORMAL_SPEED"
+
+	defaultTimeout = 10 * time.Minute
+)
+
+type EndReason string
+
+const (
+	EndReasonReturnToNormal EndReason = "return_to_normal"
+	EndReasonTimeout        EndReason = "timeout"
+	EndReasonShutdown       EndReason = "shutdown"
+)
+
+type Episode struct {
+	DeviceID     string
+	AccountID    string
+	TripID       string
+	Start        time.Time
+	End          time.Time
+	PeakSpeed    stats.Speed
+	Locations    []trip.Point
+	EventIDs     []string
+	StartMissing bool
+	EndReason    EndReason
+
+	lastAt time.Time
+}
+
+func (e *Episode) Duration() time.Duration {
+	return e.End.Sub(e.Start)
+}
+
+type Config struct {
+	Timeout   time.Duration
+	SpeedUnit stats.SpeedUnit
+	OnEpisode func(episode *Episode)
+}
+
+type Builder struct {
+	cfg Config
+
+	mu   sync.Mutex
+	open map[string]*Episode
+}
+
+func NewBuilder(cfg Config) *Builder {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.SpeedUnit == "" {
+		cfg.SpeedUnit = stats.KilometersPerHour
+	}
+	return &Builder{cfg: cfg, open: make(map[string]*Episode)}
+}
+
+func (b *Builder) Add(event *driverbehavior.Event) {
+	name := event.GetEventName()
+	switch name {
+	case EventMaxSpeedExceeded, EventPersistentMaxSpeed, EventReturnToNormalSpeed:
+	default:
+		return
+	}
+
+	deviceID := event.GetDeviceID()
+	at := event.GetCreatedAt()
+
+	b.mu.Lock()
+	// Only this device's episode is checked against the event time; other
+	// devices upload on their own schedule and close through Expire.
+	var done []*Episode
+	episode, ok := b.open[deviceID]
+	if ok && b.timedOut(episode, at) {
+		delete(b.open, deviceID)
+		done = append(done, episode)
+		ok = false
+	}
+	if !ok {
+		if name == EventReturnToNormalSpeed {
+			b.mu.Unlock()
+			b.emit(done)
+			return
+		}
+		episode = &Episode{
+			DeviceID:     deviceID,
+			AccountID:    event.GetAccountID(),
+			TripID:       event.GetTripID(),
+			Start:        at,
+			StartMissing: name != EventMaxSpeedExceeded,
+		}
+		b.open[deviceID] = episode
+	}
+
+	b.observe(episode, event.BaseEvent)
+
+	if name == EventReturnToNormalSpeed {
+		episode.End = at
+		episode.EndReason = EndReasonReturnToNormal
+		delete(b.open, deviceID)
+		done = append(done, episode)
+	}
+	b.mu.Unlock()
+
+	b.emit(done)
+}
+
+// Expire closes the episodes of every device idle at now, normally the
+// wall clock.
+func (b *Builder) Expire(now time.Time) []*Episode {
+	b.mu.Lock()
+	var done []*Episode
+	for deviceID, episode := range b.open {
+		if b.timedOut(episode, now) {
+			delete(b.open, deviceID)
+			done = append(done, episode)
+		}
+	}
+	sortEpisodes(done)
+	b.mu.Unlock()
+
+	b.emit(done)
+	return done
+}
+
+func (b *Builder) Close() []*Episode {
+	b.mu.Lock()
+	var done []*Episode
+	for deviceID, episode := range b.open {
+		episode.End = episode.lastAt
+		episode.EndReason = EndReasonShutdown
+		done = append(done, episode)
+		delete(b.open, deviceID)
+	}
+	b.mu.Unlock()
+
+	sortEpisodes(done)
+	b.emit(done)
+	return done
+}
+
+func (b *Builder) Handler() dispatch.Handler {
+	return func(ctx context.Context, event *base.BaseEvent) error {
+		if event.GetCategory() == "EVENT_CATEGORY_DRIVER_BEHAVIOR" {
+			b.Add(driverbehavior.New(event))
+		}
+		return nil
+	}
+}
+
+func (b *Builder) observe(episode *Episode, event *base.BaseEvent) {
+	at := event.GetCreatedAt()
+	if at.After(episode.lastAt) {
+		episode.lastAt = at
+	}
+	episode.EventIDs = append(episode.EventIDs, event.GetID())
+
+	coords := event.GetCoordinates()
+	if coords == nil {
+		return
+	}
+	if speed := stats.NewSpeed(coords.Speed, b.cfg.SpeedUnit); speed > episode.PeakSpeed {
+		episode.PeakSpeed = speed
+	}
+	if coords.Latitude != 0 || coords.Longitude != 0 {
+		episode.Locations = append(episode.Locations, trip.Point{Time: at, Coordinates: *coords})
+	}
+}
+
+// timedOut ends episode with EndReasonTimeout when it is idle at now.
+func (b *Builder) timedOut(episode *Episode, now time.Time) bool {
+	if now.Sub(episode.lastAt) < b.cfg.Timeout {
+		return false
+	}
+	episode.End = episode.lastAt
+	episode.EndReason = EndReasonTimeout
+	return true
+}
+
+func (b *Builder) emit(episodes []*Episode) {
+	if b.cfg.OnEpisode == nil {
+		return
+	}
+	for _, episode := range episodes {
+		b.cfg.OnEpisode(episode)
+	}
+}
+
+func sortEpisodes(episodes []*Episode) {
+	sort.Slice(episodes, func(i, j int) bool {
+		if !episodes[i].Start.Equal(episodes[j].Start) {
+			return episodes[i].Start.Before(episodes[j].Start)
+		}
+		return episodes[i].DeviceID < episodes[j].DeviceID
+	})
+}
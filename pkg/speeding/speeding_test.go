@@ -0,0 +1,124 @@
+package speeding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-eventlib/pkg/stats"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/driverbehavior"
+)
+
+var start = time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+
+func speedEvent(id, device, name string, offset time.Duration, speed float64) *base.BaseEvent {
+	return &base.BaseEvent{
+		ID:        id,
+		CreatedAt: start.Add(offset),
+		Category:  "EVENT_CATEGORY_DRIVER_BEHAVIOR",
+		Attributes: base.Attributes{
+			Device: &base.Device{ID: device, AccountID: "account-1"},
+			Data: &base.Data{TripEvent: map[string]interface{}{
+				"trip_id": "trip-1",
+				"driver_behavior": map[string]interface{}{
+					"event_name": name,
+					"detail": map[string]interface{}{
+						"location": map[string]interface{}{
+							"coordinates": map[string]interface{}{"latitude": -23.5, "longitude": -46.6, "speed": speed},
+						},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func collect() (*Builder, *[]*Episode) {
+	var episodes []*Episode
+	b := NewBuilder(Config{Timeout: 5 * time.Minute, OnEpisode: func(e *Episode) { episodes = append(episodes, e) }})
+	return b, &episodes
+}
+
+func TestBuilder_StitchesEpisode(t *testing.T) {
+	b, episodes := collect()
+
+	b.Add(driverbehavior.New(speedEvent("s", "device-1", EventMaxSpeedExceeded, 0, 95)))
+	b.Add(driverbehavior.New(speedEvent("p-1", "device-1", EventPersistentMaxSpeed, time.Minute, 112)))
+	b.Add(driverbehavior.New(speedEvent("p-2", "device-1", EventPersistentMaxSpeed, 2*time.Minute, 104)))
+	b.Add(driverbehavior.New(speedEvent("other", "device-1", "HARSH_BRAKING", 150*time.Second, 0)))
+
+	if len(*episodes) != 0 {
+		t.Fatalf("episódio emitido antes do fim: %d", len(*episodes))
+	}
+
+	b.Add(driverbehavior.New(speedEvent("e", "device-1", EventReturnToNormalSpeed, 3*time.Minute, 78)))
+	if len(*episodes) != 1 {
+		t.Fatalf("episódios = %d, esperava 1", len(*episodes))
+	}
+
+	e := (*episodes)[0]
+	if e.Duration() != 3*time.Minute || e.EndReason != EndReasonReturnToNormal || e.StartMissing {
+		t.Errorf("Duration() = %v, EndReason = %s, StartMissing = %v", e.Duration(), e.EndReason, e.StartMissing)
+	}
+	if e.PeakSpeed.In(stats.KilometersPerHour) < 111.99 || e.PeakSpeed.In(stats.KilometersPerHour) > 112.01 {
+		t.Errorf("PeakSpeed = %f km/h, esperava 112", e.PeakSpeed.In(stats.KilometersPerHour))
+	}
+	if len(e.Locations) != 4 || len(e.EventIDs) != 4 || e.TripID != "trip-1" {
+		t.Errorf("Locations = %d, EventIDs = %v, TripID = %s", len(e.Locations), e.EventIDs, e.TripID)
+	}
+}
+
+func TestBuilder_TimesOutOrphanedStart(t *testing.T) {
+	b, episodes := collect()
+
+	b.Add(driverbehavior.New(speedEvent("s-1", "device-1", EventMaxSpeedExceeded, 0, 90)))
+	b.Add(driverbehavior.New(speedEvent("s-2", "device-2", EventMaxSpeedExceeded, 2*time.Minute, 90)))
+	b.Add(driverbehavior.New(speedEvent("s-3", "device-2", EventPersistentMaxSpeed, 6*time.Minute, 90)))
+
+	// Events of device-2 do not advance the clock of device-1.
+	if len(*episodes) != 0 {
+		t.Fatalf("episódios = %+v, esperava nenhum encerrado por outro dispositivo", *episodes)
+	}
+
+	b.Add(driverbehavior.New(speedEvent("s-4", "device-1", EventMaxSpeedExceeded, 6*time.Minute, 90)))
+	if len(*episodes) != 1 || (*episodes)[0].DeviceID != "device-1" || (*episodes)[0].EndReason != EndReasonTimeout {
+		t.Fatalf("episódios = %+v, esperava device-1 encerrado por timeout", *episodes)
+	}
+	if (*episodes)[0].Duration() != 0 {
+		t.Errorf("Duration() = %v, esperava 0 para início órfão", (*episodes)[0].Duration())
+	}
+
+	expired := b.Expire(start.Add(time.Hour))
+	if len(expired) != 2 || expired[0].DeviceID != "device-2" || expired[0].Duration() != 4*time.Minute {
+		t.Errorf("Expire() = %+v, esperava device-2 com 4m e o novo episódio de device-1", expired)
+	}
+}
+
+func TestBuilder_MissingStartAndStrayEnd(t *testing.T) {
+	b, episodes := collect()
+
+	b.Add(driverbehavior.New(speedEvent("e-0", "device-1", EventReturnToNormalSpeed, 0, 60)))
+	b.Add(driverbehavior.New(speedEvent("p", "device-1", EventPersistentMaxSpeed, time.Minute, 100)))
+
+	remaining := b.Close()
+	if len(*episodes) != 1 || len(remaining) != 1 {
+		t.Fatalf("episódios = %d, Close() = %d, esperava 1", len(*episodes), len(remaining))
+	}
+	if !remaining[0].StartMissing || remaining[0].EndReason != EndReasonShutdown || len(remaining[0].EventIDs) != 1 {
+		t.Errorf("episódio = %+v, esperava início ausente encerrado no shutdown", remaining[0])
+	}
+}
+
+func TestBuilder_Handler(t *testing.T) {
+	b, episodes := collect()
+	handler := b.Handler()
+
+	handler(context.Background(), speedEvent("s", "device-1", EventMaxSpeedExceeded, 0, 90))
+	handler(context.Background(), &base.BaseEvent{ID: "dms", Category: "EVENT_CATEGORY_DMS", CreatedAt: start})
+	handler(context.Background(), speedEvent("e", "device-1", EventReturnToNormalSpeed, time.Minute, 60))
+
+	if len(*episodes) != 1 {
+		t.Errorf("episódios = %d, esperava 1", len(*episodes))
+	}
+}
@@ -0,0 +1,188 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"go-eventlib/pkg/trip"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/common"
+	"go-eventlib/pkg/types/telemetry"
+)
+
+const earthRadius = 6371008.8
+
+type SpeedUnit string
+
+const (
+	MetersPerSecond   SpeedUnit = "m/s"
+	KilometersPerHour SpeedUnit = "km/h"
+	MilesPerHour      SpeedUnit = "mph"
+	Knots             SpeedUnit = "kn"
+)
+
+var metersPerSecond = map[SpeedUnit]float64{
+	MetersPerSecond:   1,
+	KilometersPerHour: 1000.0 / 3600.0,
+	MilesPerHour:      1609.344 / 3600.0,
+	Knots:             1852.0 / 3600.0,
+}
+
+type Speed float64
+
+func NewSpeed(value float64, unit SpeedUnit) Speed {
+	factor, ok := metersPerSecond[unit]
+	if !ok {
+		factor = metersPerSecond[KilometersPerHour]
+	}
+	return Speed(value * factor)
+}
+
+func (s Speed) In(unit SpeedUnit) float64 {
+	factor, ok := metersPerSecond[unit]
+	if !ok {
+		factor = metersPerSecond[KilometersPerHour]
+	}
+	return float64(s) / factor
+}
+
+func Haversine(a, b common.Coordinates) float64 {
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := b.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+type Config struct {
+	SpeedUnit SpeedUnit
+}
+
+type Summary struct {
+	Start         time.Time
+	End           time.Time
+	Events        int
+	Points        int
+	Distance      float64
+	MaxSpeed      Speed
+	AvgSpeed      Speed
+	EngineHours   time.Duration
+	OdometerDelta float64
+	HasOdometer   bool
+}
+
+func (s Summary) DistanceKm() float64 {
+	return s.Distance / 1000
+}
+
+func Summarize(events []*base.BaseEvent, cfg Config) Summary {
+	if cfg.SpeedUnit == "" {
+		cfg.SpeedUnit = KilometersPerHour
+	}
+
+	sorted := append([]*base.BaseEvent(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].GetCreatedAt().Before(sorted[j].GetCreatedAt())
+	})
+
+	var (
+		summary    Summary
+		last       *common.Coordinates
+		speedSum   Speed
+		speeds     int
+		ignitionOn time.Time
+		odometer   []float64
+	)
+
+	for _, event := range sorted {
+		createdAt := event.GetCreatedAt()
+		if summary.Events == 0 {
+			summary.Start = createdAt
+		}
+		summary.End = createdAt
+		summary.Events++
+
+		if coords := event.GetCoordinates(); coords != nil && (coords.Latitude != 0 || coords.Longitude != 0) {
+			if last != nil {
+				summary.Distance += Haversine(*last, *coords)
+			}
+			last = coords
+			summary.Points++
+
+			speed := NewSpeed(coords.Speed, cfg.SpeedUnit)
+			speedSum += speed
+			speeds++
+			if speed > summary.MaxSpeed {
+				summary.MaxSpeed = speed
+			}
+		}
+
+		switch trip.IgnitionStatus(event) {
+		case telemetry.IgnitionStatusOn:
+			if ignitionOn.IsZero() {
+				ignitionOn = createdAt
+			}
+		case telemetry.IgnitionStatusOff:
+			if !ignitionOn.IsZero() {
+				summary.EngineHours += createdAt.Sub(ignitionOn)
+				ignitionOn = time.Time{}
+			}
+		}
+
+		if value, ok := telemetry.New(event).GetOdometer(); ok {
+			odometer = append(odometer, value)
+		}
+	}
+
+	if speeds > 0 {
+		summary.AvgSpeed = speedSum / Speed(speeds)
+	}
+	if len(odometer) > 0 {
+		summary.HasOdometer = true
+		summary.OdometerDelta = math.Max(0, odometer[len(odometer)-1]-odometer[0])
+	}
+
+	return summary
+}
+
+func ForTrip(t *trip.Trip, cfg Config) Summary {
+	return Summarize(t.Timeline, cfg)
+}
+
+type KeyFunc func(event *base.BaseEvent) string
+
+func ByTrip(event *base.BaseEvent) string {
+	return event.GetTripID()
+}
+
+func ByDevice(event *base.BaseEvent) string {
+	return event.GetDeviceID()
+}
+
+func ByDay(event *base.BaseEvent) string {
+	return event.GetCreatedAt().UTC().Format("2006-01-02")
+}
+
+func ByDeviceDay(event *base.BaseEvent) string {
+	return event.GetDeviceID() + "/" + ByDay(event)
+}
+
+func Aggregate(events []*base.BaseEvent, key KeyFunc, cfg Config) map[string]Summary {
+	groups := make(map[string][]*base.BaseEvent)
+	for _, event := range events {
+		k := key(event)
+		if k == "" {
+			continue
+		}
+		groups[k] = append(groups[k], event)
+	}
+
+	summaries := make(map[string]Summary, len(groups))
+	for k, group := range groups {
+		summaries[k] = Summarize(group, cfg)
+	}
+	return summaries
+}
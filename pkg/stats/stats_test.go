@@ -0,0 +1,169 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go-eventlib/pkg/trip"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/common"
+)
+
+var start = time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+
+func event(id, device, tripID string, offset time.Duration, name string, detail map[string]interface{}) *base.BaseEvent {
+	return &base.BaseEvent{
+		ID:        id,
+		CreatedAt: start.Add(offset),
+		Attributes: base.Attributes{
+			Device: &base.Device{ID: device},
+			Data: &base.Data{TripEvent: map[string]interface{}{
+				"trip_id": tripID,
+				"telemetry": map[string]interface{}{
+					"event_name": name,
+					"detail":     detail,
+				},
+			}},
+		},
+	}
+}
+
+func located(id, device, tripID string, offset time.Duration, lat, lon, speed float64) *base.BaseEvent {
+	return event(id, device, tripID, offset, "PERIODIC", map[string]interface{}{
+		"location": map[string]interface{}{
+			"coordinates": map[string]interface{}{"latitude": lat, "longitude": lon, "speed": speed},
+		},
+	})
+}
+
+func ignition(id, device, tripID string, offset time.Duration, status string, odometer float64) *base.BaseEvent {
+	e := event(id, device, tripID, offset, "IGNITION", nil)
+	e.Attributes.Data.TripEvent.(map[string]interface{})["telemetry"] = map[string]interface{}{
+		"event_name": "IGNITION",
+		"ignition":   map[string]interface{}{"status": status},
+	}
+	e.Attributes.Data.Telemetry = map[string]interface{}{
+		"metrics": map[string]interface{}{"odometer": map[string]interface{}{"value": odometer}},
+	}
+	return e
+}
+
+func approx(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+func TestHaversine(t *testing.T) {
+	saoPaulo := common.Coordinates{Latitude: -23.55052, Longitude: -46.633308}
+	rio := common.Coordinates{Latitude: -22.906847, Longitude: -43.172896}
+
+	if got := Haversine(saoPaulo, rio); !approx(got, 360750, 1000) {
+		t.Errorf("Haversine(SP, RJ) = %.0f m, esperava ~360750 m", got)
+	}
+	if got := Haversine(saoPaulo, saoPaulo); got != 0 {
+		t.Errorf("Haversine(SP, SP) = %f, esperava 0", got)
+	}
+}
+
+func TestSpeed_Units(t *testing.T) {
+	speed := NewSpeed(36, KilometersPerHour)
+
+	tests := []struct {
+		unit SpeedUnit
+		want float64
+	}{
+		{MetersPerSecond, 10},
+		{KilometersPerHour, 36},
+		{MilesPerHour, 22.369},
+		{Knots, 19.438},
+	}
+
+	for _, tt := range tests {
+		if got := speed.In(tt.unit); !approx(got, tt.want, 0.001) {
+			t.Errorf("In(%s) = %f, esperava %f", tt.unit, got, tt.want)
+		}
+	}
+}
+
+func testEvents() []*base.BaseEvent {
+	return []*base.BaseEvent{
+		located("p-2", "device-1", "trip-1", 20*time.Minute, -23.0, -46.0, 80),
+		ignition("on", "device-1", "trip-1", 0, "IGNITION_STATUS_ON", 125000),
+		located("p-1", "device-1", "trip-1", 10*time.Minute, -23.1, -46.0, 40),
+		ignition("off", "device-1", "trip-1", 90*time.Minute, "IGNITION_STATUS_OFF", 125012.5),
+		ignition("on-2", "device-2", "trip-2", 25*time.Hour, "IGNITION_STATUS_ON", 9000),
+		ignition("off-2", "device-2", "trip-2", 26*time.Hour, "IGNITION_STATUS_OFF", 9030),
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	summary := Summarize(testEvents()[:4], Config{})
+
+	if summary.Events != 4 || summary.Points != 2 {
+		t.Errorf("Events = %d, Points = %d, esperava 4 e 2", summary.Events, summary.Points)
+	}
+	if !approx(summary.DistanceKm(), 11.12, 0.05) {
+		t.Errorf("DistanceKm() = %f, esperava ~11.12", summary.DistanceKm())
+	}
+	if !approx(summary.MaxSpeed.In(KilometersPerHour), 80, 1e-9) || !approx(summary.AvgSpeed.In(KilometersPerHour), 60, 1e-9) {
+		t.Errorf("MaxSpeed = %f, AvgSpeed = %f, esperava 80 e 60 km/h", summary.MaxSpeed.In(KilometersPerHour), summary.AvgSpeed.In(KilometersPerHour))
+	}
+	if summary.EngineHours != 90*time.Minute {
+		t.Errorf("EngineHours = %v, esperava 1h30m", summary.EngineHours)
+	}
+	if !summary.HasOdometer || summary.OdometerDelta != 12.5 {
+		t.Errorf("OdometerDelta = %f (HasOdometer %v), esperava 12.5", summary.OdometerDelta, summary.HasOdometer)
+	}
+	if !summary.Start.Equal(start) || !summary.End.Equal(start.Add(90*time.Minute)) {
+		t.Errorf("Start = %v, End = %v", summary.Start, summary.End)
+	}
+}
+
+func TestSummarize_SpeedUnit(t *testing.T) {
+	events := []*base.BaseEvent{located("p-1", "device-1", "trip-1", 0, -23.1, -46.0, 10)}
+
+	summary := Summarize(events, Config{SpeedUnit: MetersPerSecond})
+	if got := summary.MaxSpeed.In(KilometersPerHour); !approx(got, 36, 1e-9) {
+		t.Errorf("MaxSpeed = %f km/h, esperava 36", got)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	events := testEvents()
+
+	byTrip := Aggregate(events, ByTrip, Config{})
+	if len(byTrip) != 2 || byTrip["trip-2"].EngineHours != time.Hour || byTrip["trip-2"].OdometerDelta != 30 {
+		t.Errorf("ByTrip = %+v, esperava 2 viagens com trip-2 de 1h e 30 km", byTrip)
+	}
+
+	byDevice := Aggregate(events, ByDevice, Config{})
+	if byDevice["device-1"].Events != 4 || byDevice["device-2"].Events != 2 {
+		t.Errorf("ByDevice = %+v", byDevice)
+	}
+
+	byDay := Aggregate(events, ByDay, Config{})
+	if len(byDay) != 2 || byDay["2025-03-02"].Events != 2 {
+		t.Errorf("ByDay = %+v, esperava 2 dias", byDay)
+	}
+
+	if _, ok := Aggregate(events, ByDeviceDay, Config{})["device-1/2025-03-01"]; !ok {
+		t.Error("ByDeviceDay sem a chave device-1/2025-03-01")
+	}
+}
+
+func TestForTrip(t *testing.T) {
+	trips, err := trip.Collect(func(yield func(*base.BaseEvent, error) bool) {
+		for _, e := range testEvents() {
+			if !yield(e, nil) {
+				return
+			}
+		}
+	}, trip.Config{})
+	if err != nil || len(trips) != 2 {
+		t.Fatalf("Collect() = %d viagens, %v", len(trips), err)
+	}
+
+	if got := ForTrip(trips[0], Config{}); got.EngineHours != 90*time.Minute || got.Points != 2 {
+		t.Errorf("ForTrip(trip-1) = %+v", got)
+	}
+}
@@ -0,0 +1,195 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go-eventlib/pkg/sink/jsonl"
+	"go-eventlib/pkg/types/base"
+)
+
+const dayLayout = "2006-01-02"
+
+type JSONL struct {
+	dir string
+}
+
+func NewJSONL(dir string) *JSONL {
+	return &JSONL{dir: dir}
+}
+
+// Query returns matching events in archive order: day partition, account,
+// file, then the order lines were written. Scanning stops at the first match
+// past the page, and NextCursor records its file and decompressed byte
+// offset, so the next page resumes there without reopening earlier files.
+func (s *JSONL) Query(ctx context.Context, q Query) (*Page, error) {
+	pos, err := decodeFileCursor(q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := jsonl.Files(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("store: listing %s: %w", s.dir, err)
+	}
+
+	limit := q.limit()
+	page := &Page{}
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		rel, err := filepath.Rel(s.dir, file)
+		if err != nil {
+			return nil, fmt.Errorf("store: listing %s: %w", s.dir, err)
+		}
+		rel = filepath.ToSlash(rel)
+		if pos != nil && rel < pos.file {
+			continue
+		}
+
+		partition := filepath.Dir(file)
+		if !q.matchesPartition(filepath.Base(filepath.Dir(partition)), filepath.Base(partition)) {
+			continue
+		}
+
+		var offset int64
+		if pos != nil && rel == pos.file {
+			offset = pos.offset
+		}
+
+		err = scanFile(file, offset, func(event *base.BaseEvent, at int64) bool {
+			if !q.Matches(event) {
+				return true
+			}
+			if len(page.Events) == limit {
+				page.NextCursor = encodeFileCursor(rel, at)
+				return false
+			}
+			page.Events = append(page.Events, Wrap(event))
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		if page.NextCursor != "" {
+			break
+		}
+	}
+
+	return page, nil
+}
+
+func (q Query) matchesPartition(day, account string) bool {
+	if q.AccountID != "" && account != q.AccountID && account != jsonl.UnknownAccount {
+		return false
+	}
+	if !q.From.IsZero() && day < q.From.UTC().Format(dayLayout) {
+		return false
+	}
+	if !q.To.IsZero() && day > q.To.UTC().Format(dayLayout) {
+		return false
+	}
+	return true
+}
+
+// scanFile calls fn with every event of the file from the decompressed
+// byte offset on, together with the offset of its line, until fn returns
+// false.
+func scanFile(path string, offset int64, fn func(event *base.BaseEvent, at int64) bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("store: opening %s: %w", path, err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("store: reading %s: %w", path, err)
+	}
+	defer zr.Close()
+
+	if _, err := io.CopyN(io.Discard, zr, offset); err != nil {
+		return readErr(path, err)
+	}
+
+	br := bufio.NewReaderSize(zr, 64*1024)
+	at := offset
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			start := at
+			at += int64(len(line))
+
+			if line = bytes.TrimSpace(line); len(line) == 0 {
+				continue
+			}
+			var event base.BaseEvent
+			if err := json.Unmarshal(line, &event); err != nil {
+				return fmt.Errorf("store: decoding %s: %w", path, err)
+			}
+			if !fn(&event, start) {
+				return nil
+			}
+			continue
+		}
+		// A trailing line without a newline is still being written.
+		return readErr(path, err)
+	}
+}
+
+// readErr ignores the end of a file; files still being written by the
+// archive end without a gzip trailer.
+func readErr(path string, err error) error {
+	if err == nil || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil
+	}
+	return fmt.Errorf("store: reading %s: %w", path, err)
+}
+
+type fileCursor struct {
+	file   string
+	offset int64
+}
+
+func encodeFileCursor(file string, offset int64) string {
+	raw := strconv.FormatInt(offset, 10) + "@" + file
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeFileCursor(s string) (*fileCursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	offset, file, ok := strings.Cut(string(raw), "@")
+	if !ok || file == "" {
+		return nil, ErrInvalidCursor
+	}
+	n, err := strconv.ParseInt(offset, 10, 64)
+	if err != nil || n < 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &fileCursor{file: file, offset: n}, nil
+}
@@ -0,0 +1,121 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go-eventlib/pkg/sink/sqlite"
+	"go-eventlib/pkg/types/base"
+)
+
+type SQLite struct {
+	db *sql.DB
+}
+
+func NewSQLite(db *sql.DB) *SQLite {
+	return &SQLite{db: db}
+}
+
+func (s *SQLite) Query(ctx context.Context, q Query) (*Page, error) {
+	after, err := decodeCursor(q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	tables, err := sqlite.Tables(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+	if q.Category != "" {
+		table := sqlite.TableName(q.Category)
+		if !slices.Contains(tables, table) {
+			return &Page{}, nil
+		}
+		tables = []string{table}
+	}
+	if len(tables) == 0 {
+		return &Page{}, nil
+	}
+
+	where, args := whereClause(q, after)
+	selects := make([]string, len(tables))
+	var allArgs []interface{}
+	for i, table := range tables {
+		selects[i] = `SELECT id, created_at, payload FROM ` + table + where
+		allArgs = append(allArgs, args...)
+	}
+
+	limit := q.limit()
+	stmt := strings.Join(selects, " UNION ALL ") + ` ORDER BY created_at, id LIMIT ?`
+	allArgs = append(allArgs, limit+1)
+
+	rows, err := s.db.QueryContext(ctx, stmt, allArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("store: querying sqlite: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*base.BaseEvent
+	for rows.Next() {
+		var (
+			id        string
+			createdAt int64
+			payload   string
+		)
+		if err := rows.Scan(&id, &createdAt, &payload); err != nil {
+			return nil, fmt.Errorf("store: scanning sqlite row: %w", err)
+		}
+
+		var event base.BaseEvent
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			return nil, fmt.Errorf("store: decoding event %s: %w", id, err)
+		}
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: querying sqlite: %w", err)
+	}
+
+	return newPage(events, limit), nil
+}
+
+func whereClause(q Query, after *cursor) (string, []interface{}) {
+	var (
+		conds []string
+		args  []interface{}
+	)
+
+	eq := func(column, value string) {
+		if value != "" {
+			conds = append(conds, column+" = ?")
+			args = append(args, value)
+		}
+	}
+	eq("device_id", q.DeviceID)
+	eq("account_id", q.AccountID)
+	eq("trip_id", q.TripID)
+	eq("category", string(q.Category))
+	eq("event_name", q.EventName)
+
+	if !q.From.IsZero() {
+		conds = append(conds, "created_at >= ?")
+		args = append(args, q.From.UnixNano())
+	}
+	if !q.To.IsZero() {
+		conds = append(conds, "created_at < ?")
+		args = append(args, q.To.UnixNano())
+	}
+	if after != nil {
+		conds = append(conds, "(created_at > ? OR (created_at = ? AND id > ?))")
+		args = append(args, after.createdAt, after.createdAt, after.id)
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
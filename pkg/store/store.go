@@ -0,0 +1,132 @@
+package store
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/typed"
+)
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+var ErrInvalidCursor = errors.New("store: invalid cursor")
+
+type Query struct {
+	DeviceID  string
+	AccountID string
+	TripID    string
+	Category  base.EventCategory
+	EventName string
+	From      time.Time
+	To        time.Time
+	Limit     int
+	Cursor    string
+}
+
+type Page struct {
+	Events     []base.Event
+	NextCursor string
+}
+
+type Store interface {
+	Query(ctx context.Context, q Query) (*Page, error)
+}
+
+func (q Query) limit() int {
+	switch {
+	case q.Limit <= 0:
+		return DefaultLimit
+	case q.Limit > MaxLimit:
+		return MaxLimit
+	default:
+		return q.Limit
+	}
+}
+
+func (q Query) Matches(event *base.BaseEvent) bool {
+	switch {
+	case q.DeviceID != "" && event.GetDeviceID() != q.DeviceID:
+		return false
+	case q.AccountID != "" && event.GetAccountID() != q.AccountID:
+		return false
+	case q.Category != "" && event.GetCategory() != q.Category:
+		return false
+	case !q.From.IsZero() && event.GetCreatedAt().Before(q.From):
+		return false
+	case !q.To.IsZero() && !event.GetCreatedAt().Before(q.To):
+		return false
+	case q.TripID != "" && event.GetTripID() != q.TripID:
+		return false
+	case q.EventName != "" && event.GetEventName() != q.EventName:
+		return false
+	}
+	return true
+}
+
+// Wrap is typed.Wrap, kept for callers of the store package.
+func Wrap(event *base.BaseEvent) base.Event {
+	return typed.Wrap(event)
+}
+
+type cursor struct {
+	createdAt int64
+	id        string
+}
+
+func encodeCursor(event *base.BaseEvent) string {
+	raw := strconv.FormatInt(event.GetCreatedAt().UnixNano(), 10) + "/" + event.GetID()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(s string) (*cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	nanos, id, ok := strings.Cut(string(raw), "/")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	createdAt, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	return &cursor{createdAt: createdAt, id: id}, nil
+}
+
+func (c *cursor) after(event *base.BaseEvent) bool {
+	if c == nil {
+		return true
+	}
+	createdAt := event.GetCreatedAt().UnixNano()
+	return createdAt > c.createdAt || (createdAt == c.createdAt && event.GetID() > c.id)
+}
+
+func newPage(events []*base.BaseEvent, limit int) *Page {
+	page := &Page{}
+	if len(events) > limit {
+		events = events[:limit]
+		page.NextCursor = encodeCursor(events[limit-1])
+	}
+
+	page.Events = make([]base.Event, len(events))
+	for i, event := range events {
+		page.Events[i] = Wrap(event)
+	}
+	return page
+}
@@ -0,0 +1,226 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-eventlib/pkg/sink"
+	"go-eventlib/pkg/sink/jsonl"
+	"go-eventlib/pkg/sink/sqlite"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/dms"
+	"go-eventlib/pkg/types/telemetry"
+)
+
+var start = time.Date(2025, 3, 1, 22, 0, 0, 0, time.UTC)
+
+func testEvent(i int, device, account, trip string, category base.EventCategory, name string) *base.BaseEvent {
+	return &base.BaseEvent{
+		ID:        fmt.Sprintf("event-%02d", i),
+		CreatedAt: start.Add(time.Duration(i) * time.Hour),
+		Category:  category,
+		Attributes: base.Attributes{
+			Device: &base.Device{ID: device, AccountID: account},
+			Data: &base.Data{TripEvent: map[string]interface{}{
+				"trip_id": trip,
+				"dms":     map[string]interface{}{"event_name": name},
+			}},
+		},
+	}
+}
+
+func testEvents() []*base.BaseEvent {
+	return []*base.BaseEvent{
+		testEvent(0, "device-1", "account-a", "trip-1", "EVENT_CATEGORY_DMS", "DROWSINESS"),
+		testEvent(1, "device-1", "account-a", "trip-1", "EVENT_CATEGORY_DMS", "YAWNING"),
+		testEvent(2, "device-1", "account-a", "trip-1", "EVENT_CATEGORY_TELEMETRY", ""),
+		testEvent(3, "device-2", "account-a", "trip-2", "EVENT_CATEGORY_DMS", "DROWSINESS"),
+		testEvent(4, "device-3", "account-b", "trip-3", "EVENT_CATEGORY_DMS", "ON_PHONE"),
+		testEvent(5, "device-1", "account-a", "trip-4", "EVENT_CATEGORY_DMS", "DROWSINESS"),
+	}
+}
+
+func fill(t *testing.T, s sink.EventSink) {
+	t.Helper()
+
+	for _, event := range testEvents() {
+		if err := s.Write(context.Background(), event); err != nil {
+			t.Fatalf("Write() erro inesperado: %v", err)
+		}
+	}
+	if err := s.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() erro inesperado: %v", err)
+	}
+}
+
+func stores(t *testing.T) map[string]Store {
+	t.Helper()
+
+	db, err := sqlite.Open(sqlite.Config{Path: filepath.Join(t.TempDir(), "events.db")})
+	if err != nil {
+		t.Fatalf("sqlite.Open() erro inesperado: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	fill(t, db)
+
+	dir := t.TempDir()
+	archive, err := jsonl.New(jsonl.Config{Dir: dir})
+	if err != nil {
+		t.Fatalf("jsonl.New() erro inesperado: %v", err)
+	}
+	fill(t, archive)
+	if err := archive.Close(); err != nil {
+		t.Fatalf("Close() erro inesperado: %v", err)
+	}
+
+	return map[string]Store{
+		"sqlite": NewSQLite(db.DB()),
+		"jsonl":  NewJSONL(dir),
+	}
+}
+
+func ids(page *Page) []string {
+	var got []string
+	for _, event := range page.Events {
+		got = append(got, event.GetID())
+	}
+	return got
+}
+
+func TestStore_Query(t *testing.T) {
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"todos", Query{}, []string{"event-00", "event-01", "event-02", "event-03", "event-04", "event-05"}},
+		{"dispositivo", Query{DeviceID: "device-1"}, []string{"event-00", "event-01", "event-02", "event-05"}},
+		{"conta", Query{AccountID: "account-b"}, []string{"event-04"}},
+		{"viagem", Query{TripID: "trip-1"}, []string{"event-00", "event-01", "event-02"}},
+		{"categoria", Query{Category: "EVENT_CATEGORY_TELEMETRY"}, []string{"event-02"}},
+		{"categoria inexistente", Query{Category: "EVENT_CATEGORY_ORDER"}, nil},
+		{"nome do evento", Query{EventName: "DROWSINESS"}, []string{"event-00", "event-03", "event-05"}},
+		{"intervalo", Query{From: start.Add(time.Hour), To: start.Add(4 * time.Hour)}, []string{"event-01", "event-02", "event-03"}},
+		{"combinado", Query{DeviceID: "device-1", EventName: "DROWSINESS", From: start.Add(time.Hour)}, []string{"event-05"}},
+	}
+
+	// The JSONL store returns archive order: day, then account partition.
+	archiveOrder := map[string][]string{
+		"todos": {"event-00", "event-01", "event-02", "event-03", "event-05", "event-04"},
+	}
+
+	for name, s := range stores(t) {
+		for _, tt := range tests {
+			page, err := s.Query(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("%s/%s: Query() erro inesperado: %v", name, tt.name, err)
+			}
+			want := tt.want
+			if order, ok := archiveOrder[tt.name]; ok && name == "jsonl" {
+				want = order
+			}
+			if got := ids(page); fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("%s/%s: Query() = %v, esperava %v", name, tt.name, got, want)
+			}
+			if page.NextCursor != "" {
+				t.Errorf("%s/%s: NextCursor = %q, esperava vazio", name, tt.name, page.NextCursor)
+			}
+		}
+	}
+}
+
+func TestStore_Pagination(t *testing.T) {
+	for name, s := range stores(t) {
+		var got []string
+		q := Query{DeviceID: "device-1", Limit: 3}
+
+		for pages := 0; ; pages++ {
+			if pages > 3 {
+				t.Fatalf("%s: paginação não terminou", name)
+			}
+			page, err := s.Query(context.Background(), q)
+			if err != nil {
+				t.Fatalf("%s: Query() erro inesperado: %v", name, err)
+			}
+			got = append(got, ids(page)...)
+			if page.NextCursor == "" {
+				break
+			}
+			q.Cursor = page.NextCursor
+		}
+
+		want := []string{"event-00", "event-01", "event-02", "event-05"}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("%s: páginas = %v, esperava %v", name, got, want)
+		}
+	}
+}
+
+func TestJSONL_CursorResumesInFile(t *testing.T) {
+	dir := t.TempDir()
+	archive, err := jsonl.New(jsonl.Config{Dir: dir})
+	if err != nil {
+		t.Fatalf("jsonl.New() erro inesperado: %v", err)
+	}
+	fill(t, archive)
+	archive.Close()
+
+	s := NewJSONL(dir)
+	page, err := s.Query(context.Background(), Query{AccountID: "account-a", Limit: 3})
+	if err != nil {
+		t.Fatalf("Query() erro inesperado: %v", err)
+	}
+	if got := ids(page); fmt.Sprint(got) != "[event-00 event-01 event-02]" || page.NextCursor == "" {
+		t.Fatalf("primeira página = %v (cursor %q)", got, page.NextCursor)
+	}
+
+	// The next page must not reopen files before the cursor.
+	files, _ := jsonl.Files(dir)
+	if err := os.WriteFile(files[0], []byte("corrompido"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	page, err = s.Query(context.Background(), Query{AccountID: "account-a", Limit: 3, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("Query() erro inesperado: %v", err)
+	}
+	if got := ids(page); fmt.Sprint(got) != "[event-03 event-05]" || page.NextCursor != "" {
+		t.Errorf("segunda página = %v (cursor %q), esperava [event-03 event-05]", got, page.NextCursor)
+	}
+}
+
+func TestStore_TypedWrappers(t *testing.T) {
+	for name, s := range stores(t) {
+		page, err := s.Query(context.Background(), Query{TripID: "trip-1"})
+		if err != nil {
+			t.Fatalf("%s: Query() erro inesperado: %v", name, err)
+		}
+		if len(page.Events) != 3 {
+			t.Fatalf("%s: %d eventos, esperava 3", name, len(page.Events))
+		}
+
+		event, ok := page.Events[0].(*dms.Event)
+		if !ok {
+			t.Fatalf("%s: Events[0] = %T, esperava *dms.Event", name, page.Events[0])
+		}
+		if event.GetEventName() != "DROWSINESS" {
+			t.Errorf("%s: GetEventName() = %s, esperava DROWSINESS", name, event.GetEventName())
+		}
+		if _, ok := page.Events[2].(*telemetry.Event); !ok {
+			t.Errorf("%s: Events[2] = %T, esperava *telemetry.Event", name, page.Events[2])
+		}
+	}
+}
+
+func TestStore_InvalidCursor(t *testing.T) {
+	for name, s := range stores(t) {
+		if _, err := s.Query(context.Background(), Query{Cursor: "!!"}); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: Query() = %v, esperava ErrInvalidCursor", name, err)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package tamper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/connection"
+	"go-eventlib/pkg/types/telemetry"
+	"go-eventlib/pkg/types/vision"
+)
+
+const EventName = "TAMPER_SUSPECTED"
+
+type Signal string
+
+const (
+	VehicleBatteryDisconnected Signal = "VEHICLE_BATTERY_DISCONNECTED"
+	SIMRemoved                 Signal = "SIM_REMOVED"
+	CameraObstructedIgnitionOn Signal = "CAMERA_OBSTRUCTED_IGNITION_ON"
+	CameraObstructedLong       Signal = "CAMERA_OBSTRUCTED_LONG"
+)
+
+// Rule matches when at least Min of its Signals (all of them when Min is
+// zero) were seen for a device within Config.Window.
+type Rule struct {
+	Name    string
+	Signals []Signal
+	Min     int
+}
+
+func DefaultRules() []Rule {
+	return []Rule{
+		{Name: "power_and_sim_cut", Signals: []Signal{VehicleBatteryDisconnected, SIMRemoved}},
+		{Name: "camera_covered_while_driving", Signals: []Signal{CameraObstructedIgnitionOn}},
+		{Name: "camera_covered", Signals: []Signal{CameraObstructedLong}},
+	}
+}
+
+type Config struct {
+	Window              time.Duration
+	ObstructionDuration time.Duration
+	Rules               []Rule
+	Handler             dispatch.Handler
+}
+
+type Suspicion struct {
+	DeviceID  string
+	AccountID string
+	TripID    string
+	At        time.Time
+	Rules     []string
+	Signals   []Signal
+	EventIDs  []string
+}
+
+func (s *Suspicion) Event() *base.BaseEvent {
+	signals := make([]string, len(s.Signals))
+	for i, signal := range s.Signals {
+		signals[i] = string(signal)
+	}
+
+	return &base.BaseEvent{
+		ID:        fmt.Sprintf("tamper-%s-%d", s.DeviceID, s.At.UnixNano()),
+		Status:    "STATUS_RECEIVED",
+		CreatedAt: s.At,
+		Type:      "EVENT_TYPE_DERIVED",
+		Category:  "EVENT_CATEGORY_ALERT",
+		Sub:       "EVENT_SUB_ALERT_CRITICAL",
+		Attributes: base.Attributes{
+			Device: &base.Device{ID: s.DeviceID, AccountID: s.AccountID},
+			Data: &base.Data{
+				GroupName: "STANDALONE_EVENT",
+				StandaloneEvent: map[string]interface{}{
+					"event_group_name": "ALERT",
+					"alert": map[string]interface{}{
+						"event_name": EventName,
+						"timestamp":  s.At.Format(time.RFC3339Nano),
+						"tamper_suspected": map[string]interface{}{
+							"name":      EventName,
+							"trip_id":   s.TripID,
+							"rules":     s.Rules,
+							"signals":   signals,
+							"event_ids": s.EventIDs,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+type occurrence struct {
+	signal Signal
+	at     time.Time
+	id     string
+}
+
+// obstruction keeps only the first and the latest CAMERA_OBSTRUCTED of an
+// ongoing obstruction, since a covered camera keeps reporting it.
+type obstruction struct {
+	first, latest occurrence
+}
+
+type device struct {
+	accountID   string
+	tripID      string
+	ignition    telemetry.IgnitionStatus
+	signals     []occurrence
+	obstructed  *obstruction
+	longFired   bool
+	suspectedAt time.Time
+}
+
+type Detector struct {
+	cfg Config
+
+	mu      sync.Mutex
+	devices map[string]*device
+}
+
+func NewDetector(cfg Config) *Detector {
+	if cfg.Window <= 0 {
+		cfg.Window = 10 * time.Minute
+	}
+	if cfg.ObstructionDuration <= 0 {
+		cfg.ObstructionDuration = 30 * time.Second
+	}
+	if cfg.Rules == nil {
+		cfg.Rules = DefaultRules()
+	}
+
+	return &Detector{
+		cfg:     cfg,
+		devices: make(map[string]*device),
+	}
+}
+
+func (d *Detector) Add(event *base.BaseEvent) *Suspicion {
+	deviceID := event.GetDeviceID()
+	if deviceID == "" || event.Type == "EVENT_TYPE_DERIVED" {
+		return nil
+	}
+
+	at := event.GetCreatedAt()
+	id := event.GetID()
+	name := event.GetEventName()
+	ignition := telemetry.New(event).GetCurrentIgnitionStatus()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	dev, ok := d.devices[deviceID]
+	if !ok {
+		dev = &device{}
+		d.devices[deviceID] = dev
+	}
+	if accountID := event.GetAccountID(); accountID != "" {
+		dev.accountID = accountID
+	}
+	if tripID := event.GetTripID(); tripID != "" {
+		dev.tripID = tripID
+	}
+	turnedOn := ignition == telemetry.IgnitionStatusOn && dev.ignition != telemetry.IgnitionStatusOn
+	if ignition != "" {
+		dev.ignition = ignition
+	}
+	if turnedOn && dev.obstructed != nil {
+		// The camera was covered before the ignition came on.
+		dev.record(occurrence{signal: CameraObstructedIgnitionOn, at: at, id: dev.obstructed.latest.id})
+	}
+
+	switch {
+	case telemetry.New(event).IsVehicleBatteryDisconnected():
+		dev.record(occurrence{signal: VehicleBatteryDisconnected, at: at, id: id})
+	case simRemoved(event, name):
+		dev.record(occurrence{signal: SIMRemoved, at: at, id: id})
+	case event.GetCategory() == "EVENT_CATEGORY_VISION":
+		if vision.New(event).GetEventName() != "CAMERA_OBSTRUCTED" {
+			dev.obstructed = nil
+			dev.longFired = false
+			break
+		}
+		o := occurrence{at: at, id: id}
+		switch {
+		case dev.obstructed == nil:
+			dev.obstructed = &obstruction{first: o, latest: o}
+		case at.Before(dev.obstructed.first.at):
+			dev.obstructed.first = o
+		case !at.Before(dev.obstructed.latest.at):
+			dev.obstructed.latest = o
+		}
+		if dev.ignition == telemetry.IgnitionStatusOn {
+			o.signal = CameraObstructedIgnitionOn
+			dev.record(o)
+		}
+	}
+
+	return d.evaluateLocked(deviceID, dev, at)
+}
+
+// Expire evaluates every device at now, so a camera that stays covered
+// without further events is still reported after ObstructionDuration.
+func (d *Detector) Expire(now time.Time) []*Suspicion {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ids := make([]string, 0, len(d.devices))
+	for deviceID := range d.devices {
+		ids = append(ids, deviceID)
+	}
+	sort.Strings(ids)
+
+	var suspicions []*Suspicion
+	for _, deviceID := range ids {
+		dev := d.devices[deviceID]
+		if s := d.evaluateLocked(deviceID, dev, now); s != nil {
+			suspicions = append(suspicions, s)
+		}
+		if len(dev.signals) == 0 && dev.obstructed == nil && now.Sub(dev.suspectedAt) >= d.cfg.Window {
+			delete(d.devices, deviceID)
+		}
+	}
+	return suspicions
+}
+
+func (d *Detector) Handler() dispatch.Handler {
+	return func(ctx context.Context, event *base.BaseEvent) error {
+		s := d.Add(event)
+		if s == nil || d.cfg.Handler == nil {
+			return nil
+		}
+		return d.cfg.Handler(ctx, s.Event())
+	}
+}
+
+func (d *Detector) evaluateLocked(deviceID string, dev *device, now time.Time) *Suspicion {
+	if dev.obstructed != nil && !dev.longFired && now.Sub(dev.obstructed.first.at) >= d.cfg.ObstructionDuration {
+		dev.longFired = true
+		// The signal dates from when the obstruction crossed the limit.
+		at := dev.obstructed.first.at.Add(d.cfg.ObstructionDuration)
+		dev.record(occurrence{signal: CameraObstructedLong, at: at, id: dev.obstructed.first.id})
+		if latest := dev.obstructed.latest; latest.id != dev.obstructed.first.id {
+			dev.record(occurrence{signal: CameraObstructedLong, at: at, id: latest.id})
+		}
+	}
+
+	cutoff := now.Add(-d.cfg.Window)
+	i := sort.Search(len(dev.signals), func(i int) bool { return dev.signals[i].at.After(cutoff) })
+	dev.signals = dev.signals[i:]
+
+	if !dev.suspectedAt.IsZero() && now.Sub(dev.suspectedAt) < d.cfg.Window {
+		return nil
+	}
+
+	seen := make(map[Signal]bool)
+	for _, o := range dev.signals {
+		seen[o.signal] = true
+	}
+
+	var (
+		rules   []string
+		matched = make(map[Signal]bool)
+	)
+	for _, rule := range d.cfg.Rules {
+		min := rule.Min
+		if min <= 0 {
+			min = len(rule.Signals)
+		}
+		var hits []Signal
+		for _, signal := range rule.Signals {
+			if seen[signal] {
+				hits = append(hits, signal)
+			}
+		}
+		if len(hits) == 0 || len(hits) < min {
+			continue
+		}
+		rules = append(rules, rule.Name)
+		for _, signal := range hits {
+			matched[signal] = true
+		}
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	s := &Suspicion{
+		DeviceID:  deviceID,
+		AccountID: dev.accountID,
+		TripID:    dev.tripID,
+		At:        now,
+		Rules:     rules,
+	}
+	ids := make(map[string]bool)
+	for _, o := range dev.signals {
+		if !matched[o.signal] {
+			continue
+		}
+		if !containsSignal(s.Signals, o.signal) {
+			s.Signals = append(s.Signals, o.signal)
+		}
+		if !ids[o.id] {
+			ids[o.id] = true
+			s.EventIDs = append(s.EventIDs, o.id)
+		}
+	}
+
+	dev.suspectedAt = now
+	dev.signals = nil
+	return s
+}
+
+func (dev *device) record(o occurrence) {
+	i := sort.Search(len(dev.signals), func(i int) bool { return dev.signals[i].at.After(o.at) })
+	dev.signals = append(dev.signals, occurrence{})
+	copy(dev.signals[i+1:], dev.signals[i:])
+	dev.signals[i] = o
+}
+
+func simRemoved(event *base.BaseEvent, name string) bool {
+	if name == "SIM_CARD_REMOVED" {
+		return true
+	}
+	sim := connection.New(event).GetSimCard()
+	return sim != nil && strings.TrimPrefix(sim.Status, "SIM_CARD_STATUS_") == "ABSENT"
+}
+
+func containsSignal(signals []Signal, signal Signal) bool {
+	for _, s := range signals {
+		if s == signal {
+			return true
+		}
+	}
+	return false
+}
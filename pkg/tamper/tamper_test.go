@@ -0,0 +1,154 @@
+package tamper
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"testing"
+	"time"
+
+	"go-eventlib/pkg/eventio"
+	"go-eventlib/pkg/types/base"
+)
+
+var start = time.Date(2025, 12, 15, 19, 0, 0, 0, time.UTC)
+
+func fixture(t *testing.T, name string, offset time.Duration) *base.BaseEvent {
+	t.Helper()
+
+	for event, err := range eventio.ReadFile("../../test/events/" + name + ".json") {
+		if err != nil {
+			t.Fatalf("ReadFile(%s) erro inesperado: %v", name, err)
+		}
+		event.ID = fmt.Sprintf("%s-%d", path.Base(name), offset)
+		event.Attributes.Device.ID = "device-1"
+		event.CreatedAt = start.Add(offset)
+		return event
+	}
+	t.Fatalf("ReadFile(%s) não retornou eventos", name)
+	return nil
+}
+
+func ignitionOff(event *base.BaseEvent) *base.BaseEvent {
+	event.Attributes.Data.Telemetry = map[string]interface{}{"status": "IGNITION_STATUS_OFF"}
+	return event
+}
+
+func TestDetector_BatteryAndSIMWithinWindow(t *testing.T) {
+	var emitted []*base.BaseEvent
+	d := NewDetector(Config{
+		Window: 5 * time.Minute,
+		Handler: func(ctx context.Context, event *base.BaseEvent) error {
+			emitted = append(emitted, event)
+			return nil
+		},
+	})
+	handler := d.Handler()
+
+	handler(context.Background(), fixture(t, "hardware-events/hardware-vehicle-battery-disconnected", 0))
+	handler(context.Background(), fixture(t, "hardware-events/hardware-simcard-removed", 2*time.Minute))
+	handler(context.Background(), fixture(t, "hardware-events/hardware-simcard-removed", 3*time.Minute))
+
+	if len(emitted) != 1 {
+		t.Fatalf("len(emitted) = %d, esperava um único TAMPER_SUSPECTED", len(emitted))
+	}
+	if emitted[0].GetEventName() != EventName || emitted[0].GetDeviceID() != "device-1" {
+		t.Errorf("Event() = %+v", emitted[0])
+	}
+
+	s := d.Add(fixture(t, "hardware-events/hardware-vehicle-battery-disconnected", 20*time.Minute))
+	if s != nil {
+		t.Errorf("Add() = %+v, bateria sozinha não deveria disparar", s)
+	}
+}
+
+func TestDetector_SuspicionListsContributingEvents(t *testing.T) {
+	d := NewDetector(Config{Window: 5 * time.Minute})
+
+	d.Add(fixture(t, "hardware-events/hardware-simcard-removed", 0))
+	if s := d.Add(fixture(t, "hardware-events/hardware-vehicle-battery-disconnected", 10*time.Minute)); s != nil {
+		t.Fatalf("Add() = %+v, sinais fora da janela não deveriam correlacionar", s)
+	}
+
+	s := d.Add(fixture(t, "hardware-events/hardware-simcard-removed", 12*time.Minute))
+	if s == nil {
+		t.Fatal("Add() = nil, esperava suspeita")
+	}
+	if len(s.Rules) != 1 || s.Rules[0] != "power_and_sim_cut" {
+		t.Errorf("Rules = %v", s.Rules)
+	}
+	want := []string{"hardware-vehicle-battery-disconnected-600000000000", "hardware-simcard-removed-720000000000"}
+	if len(s.EventIDs) != 2 || s.EventIDs[0] != want[0] || s.EventIDs[1] != want[1] {
+		t.Errorf("EventIDs = %v, esperava %v", s.EventIDs, want)
+	}
+}
+
+func TestDetector_CameraObstructed(t *testing.T) {
+	d := NewDetector(Config{ObstructionDuration: 30 * time.Second})
+
+	s := d.Add(fixture(t, "vision-basic-events/vision-camera-obstructed", 0))
+	if s == nil || s.Rules[0] != "camera_covered_while_driving" || s.Signals[0] != CameraObstructedIgnitionOn {
+		t.Fatalf("Add() = %+v, esperava câmera obstruída com ignição ligada", s)
+	}
+
+	d = NewDetector(Config{ObstructionDuration: 30 * time.Second})
+	d.Add(ignitionOff(fixture(t, "vision-basic-events/vision-camera-obstructed", 0)))
+	d.Add(ignitionOff(fixture(t, "vision-basic-events/vision-face-detected", 10*time.Second)))
+	if got := d.Expire(start.Add(time.Minute)); len(got) != 0 {
+		t.Fatalf("Expire() = %+v, obstrução encerrada antes do limite", got)
+	}
+
+	d.Add(ignitionOff(fixture(t, "vision-basic-events/vision-camera-obstructed", 2*time.Minute)))
+	got := d.Expire(start.Add(2*time.Minute + 40*time.Second))
+	if len(got) != 1 || got[0].Rules[0] != "camera_covered" || len(got[0].EventIDs) != 1 {
+		t.Fatalf("Expire() = %+v, esperava camera_covered", got)
+	}
+}
+
+func TestDetector_CustomRuleMinimum(t *testing.T) {
+	d := NewDetector(Config{Rules: []Rule{{
+		Name:    "any_two",
+		Signals: []Signal{VehicleBatteryDisconnected, SIMRemoved, CameraObstructedIgnitionOn},
+		Min:     2,
+	}}})
+
+	if s := d.Add(fixture(t, "vision-basic-events/vision-camera-obstructed", 0)); s != nil {
+		t.Fatalf("Add() = %+v, um sinal não atinge o mínimo", s)
+	}
+	s := d.Add(fixture(t, "hardware-events/hardware-simcard-removed", time.Minute))
+	if s == nil || len(s.Signals) != 2 {
+		t.Fatalf("Add() = %+v, esperava dois sinais", s)
+	}
+}
+
+func TestDetector_IgnitionOnDuringObstruction(t *testing.T) {
+	d := NewDetector(Config{ObstructionDuration: time.Hour})
+
+	if s := d.Add(ignitionOff(fixture(t, "vision-basic-events/vision-camera-obstructed", 0))); s != nil {
+		t.Fatalf("Add() = %+v, ignição desligada não deveria disparar", s)
+	}
+	s := d.Add(fixture(t, "telemetry-events/telemetry-ignition", 10*time.Second))
+	if s == nil || s.Rules[0] != "camera_covered_while_driving" {
+		t.Fatalf("Add() = %+v, esperava câmera obstruída ao ligar a ignição", s)
+	}
+	if len(s.EventIDs) != 1 || s.EventIDs[0] != "vision-camera-obstructed-0" {
+		t.Errorf("EventIDs = %v, esperava a obstrução em andamento", s.EventIDs)
+	}
+}
+
+func TestDetector_LongObstructionKeepsFirstAndLatest(t *testing.T) {
+	d := NewDetector(Config{ObstructionDuration: 30 * time.Second})
+
+	for offset := time.Duration(0); offset < 30*time.Second; offset += 5 * time.Second {
+		d.Add(ignitionOff(fixture(t, "vision-basic-events/vision-camera-obstructed", offset)))
+	}
+
+	got := d.Expire(start.Add(time.Minute))
+	if len(got) != 1 || got[0].Rules[0] != "camera_covered" {
+		t.Fatalf("Expire() = %+v, esperava camera_covered", got)
+	}
+	want := []string{"vision-camera-obstructed-0", "vision-camera-obstructed-25000000000"}
+	if ids := got[0].EventIDs; len(ids) != 2 || ids[0] != want[0] || ids[1] != want[1] {
+		t.Errorf("EventIDs = %v, esperava %v", ids, want)
+	}
+}
@@ -0,0 +1,136 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/sink"
+)
+
+// Resources are the handlers and sinks a tenant file can refer to by name.
+type Resources struct {
+	Handlers map[string]dispatch.Handler
+	Sinks    map[string]sink.EventSink
+}
+
+type tenantConfig struct {
+	AccountID      string    `yaml:"account_id"`
+	Secret         string    `yaml:"secret,omitempty"`
+	SecretEnv      string    `yaml:"secret_env,omitempty"`
+	Handlers       []string  `yaml:"handlers,omitempty"`
+	Sink           string    `yaml:"sink,omitempty"`
+	RateLimit      RateLimit `yaml:"rate_limit,omitempty"`
+	DedupNamespace string    `yaml:"dedup_namespace,omitempty"`
+}
+
+// Load reads tenants from a YAML (or JSON) document with a top-level
+// `tenants` list, resolving handler and sink names against res.
+func Load(r io.Reader, res Resources) (StaticProvider, error) {
+	var doc struct {
+		Tenants []tenantConfig `yaml:"tenants"`
+	}
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	tenants := make(StaticProvider, len(doc.Tenants))
+	for i, c := range doc.Tenants {
+		t, err := c.resolve(res)
+		switch {
+		case c.AccountID == "":
+			return nil, fmt.Errorf("%w: tenant %d has no account_id", ErrInvalidConfig, i)
+		case tenants[c.AccountID] != nil:
+			return nil, fmt.Errorf("%w: duplicate account_id %q", ErrInvalidConfig, c.AccountID)
+		case err != nil:
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidConfig, c.AccountID, err)
+		}
+		tenants[c.AccountID] = t
+	}
+	return tenants, nil
+}
+
+func (c tenantConfig) resolve(res Resources) (*Tenant, error) {
+	t := &Tenant{
+		AccountID:      c.AccountID,
+		Secret:         c.Secret,
+		RateLimit:      c.RateLimit,
+		DedupNamespace: c.DedupNamespace,
+	}
+
+	if c.SecretEnv != "" {
+		secret, ok := os.LookupEnv(c.SecretEnv)
+		if !ok {
+			return nil, fmt.Errorf("secret_env %s is not set", c.SecretEnv)
+		}
+		t.Secret = secret
+	}
+	if c.RateLimit.Rate < 0 || c.RateLimit.Burst < 0 {
+		return nil, errors.New("rate_limit must not be negative")
+	}
+
+	for _, name := range c.Handlers {
+		handler, ok := res.Handlers[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown handler %q", name)
+		}
+		t.Handlers = append(t.Handlers, handler)
+	}
+	if c.Sink != "" {
+		s, ok := res.Sinks[c.Sink]
+		if !ok {
+			return nil, fmt.Errorf("unknown sink %q", c.Sink)
+		}
+		t.Sink = s
+	}
+	return t, nil
+}
+
+// FileProvider serves tenants loaded from a file. Reload re-reads it and
+// keeps the previous tenants on error.
+type FileProvider struct {
+	name string
+	res  Resources
+
+	mu      sync.RWMutex
+	tenants StaticProvider
+}
+
+func NewFileProvider(name string, res Resources) (*FileProvider, error) {
+	p := &FileProvider{name: name, res: res}
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *FileProvider) Reload() error {
+	f, err := os.Open(p.name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tenants, err := Load(f, p.res)
+	if err != nil {
+		return fmt.Errorf("%s: %w", p.name, err)
+	}
+
+	p.mu.Lock()
+	p.tenants = tenants
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *FileProvider) Tenant(ctx context.Context, accountID string) (*Tenant, error) {
+	p.mu.RLock()
+	tenants := p.tenants
+	p.mu.RUnlock()
+	return tenants.Tenant(ctx, accountID)
+}
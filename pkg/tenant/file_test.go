@@ -0,0 +1,112 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/sink"
+	"go-eventlib/pkg/types/base"
+)
+
+const tenantsYAML = `
+tenants:
+  - account_id: account-a
+    secret_env: TENANT_TEST_SECRET
+    handlers: [alerts, audit]
+    sink: archive
+    rate_limit: {rate: 50, burst: 100}
+  - account_id: account-b
+    secret: segredo-b
+    dedup_namespace: shared
+`
+
+func testResources() Resources {
+	noop := func(ctx context.Context, event *base.BaseEvent) error { return nil }
+	return Resources{
+		Handlers: map[string]dispatch.Handler{"alerts": noop, "audit": noop},
+		Sinks:    map[string]sink.EventSink{"archive": &recorder{}},
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv("TENANT_TEST_SECRET", "segredo-a")
+
+	tenants, err := Load(strings.NewReader(tenantsYAML), testResources())
+	if err != nil {
+		t.Fatalf("Load() erro inesperado: %v", err)
+	}
+
+	a, err := tenants.Tenant(context.Background(), "account-a")
+	if err != nil {
+		t.Fatalf("Tenant() erro inesperado: %v", err)
+	}
+	if a.Secret != "segredo-a" || len(a.Handlers) != 2 || a.Sink == nil || a.RateLimit != (RateLimit{Rate: 50, Burst: 100}) {
+		t.Errorf("account-a = %+v", a)
+	}
+	if a.namespace() != "account-a" {
+		t.Errorf("namespace() = %s, esperava account-a", a.namespace())
+	}
+
+	b, _ := tenants.Tenant(context.Background(), "account-b")
+	if b == nil || b.Secret != "segredo-b" || b.namespace() != "shared" || b.Sink != nil {
+		t.Errorf("account-b = %+v", b)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{"sem conta", "tenants:\n  - secret: x\n"},
+		{"duplicada", "tenants:\n  - account_id: a\n  - account_id: a\n"},
+		{"handler desconhecido", "tenants:\n  - account_id: a\n    handlers: [missing]\n"},
+		{"sink desconhecido", "tenants:\n  - account_id: a\n    sink: missing\n"},
+		{"variável ausente", "tenants:\n  - account_id: a\n    secret_env: TENANT_TEST_UNSET\n"},
+		{"limite negativo", "tenants:\n  - account_id: a\n    rate_limit: {rate: -1}\n"},
+		{"yaml inválido", "tenants: [\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(strings.NewReader(tt.yaml), testResources()); !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("Load() erro = %v, esperava ErrInvalidConfig", err)
+			}
+		})
+	}
+}
+
+func TestFileProvider_Reload(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "tenants.yaml")
+	if err := os.WriteFile(name, []byte("tenants:\n  - account_id: account-a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := NewFileProvider(name, Resources{})
+	if err != nil {
+		t.Fatalf("NewFileProvider() erro inesperado: %v", err)
+	}
+	if _, err := p.Tenant(context.Background(), "account-b"); !errors.Is(err, ErrUnknownTenant) {
+		t.Errorf("Tenant() erro = %v, esperava ErrUnknownTenant", err)
+	}
+
+	os.WriteFile(name, []byte("tenants:\n  - account_id: account-b\n    sink: missing\n"), 0o644)
+	if err := p.Reload(); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("Reload() erro = %v, esperava ErrInvalidConfig", err)
+	}
+	if _, err := p.Tenant(context.Background(), "account-a"); err != nil {
+		t.Errorf("Tenant() erro = %v, esperava manter tenants anteriores", err)
+	}
+
+	os.WriteFile(name, []byte("tenants:\n  - account_id: account-b\n"), 0o644)
+	if err := p.Reload(); err != nil {
+		t.Fatalf("Reload() erro inesperado: %v", err)
+	}
+	if _, err := p.Tenant(context.Background(), "account-b"); err != nil {
+		t.Errorf("Tenant() erro = %v após reload", err)
+	}
+}
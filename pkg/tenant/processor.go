@@ -0,0 +1,267 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/webhook"
+)
+
+const AccountHeader = "X-Account-ID"
+
+type Config struct {
+	// DedupWindow is how long an event ID is remembered per namespace.
+	DedupWindow time.Duration
+	// Account extracts the account a request is addressed to. The default
+	// reads the {account} path value and falls back to X-Account-ID.
+	Account func(r *http.Request) string
+	Now     func() time.Time
+}
+
+// Processor is a webhook.Processor that resolves the tenant of every event
+// from attributes.device.account_id and runs it through that tenant's rate
+// limit, dedup namespace, sink and handlers only.
+type Processor struct {
+	provider Provider
+	cfg      Config
+
+	mu       sync.Mutex
+	limiters map[string]*limiter
+	seen     map[string]*window
+}
+
+func NewProcessor(provider Provider, cfg Config) *Processor {
+	if cfg.DedupWindow <= 0 {
+		cfg.DedupWindow = 10 * time.Minute
+	}
+	if cfg.Account == nil {
+		cfg.Account = accountFromRequest
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+
+	return &Processor{
+		provider: provider,
+		cfg:      cfg,
+		limiters: make(map[string]*limiter),
+		seen:     make(map[string]*window),
+	}
+}
+
+func accountFromRequest(r *http.Request) string {
+	if account := r.PathValue("account"); account != "" {
+		return account
+	}
+	return r.Header.Get(AccountHeader)
+}
+
+// ProcessEvent decodes and validates data, then delivers it to its tenant.
+// Under a context bound with WithAccount, events of any other account are
+// rejected. Duplicates of a delivered event within the tenant's dedup
+// namespace are accepted without being delivered again; a duplicate that
+// arrives while the event is still being delivered fails with ErrInFlight
+// (429) so the sender retries it.
+func (p *Processor) ProcessEvent(ctx context.Context, data []byte) (*base.BaseEvent, error) {
+	event, err := webhook.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := webhook.Validate(event); err != nil {
+		return event, err
+	}
+
+	accountID := event.GetAccountID()
+	if accountID == "" {
+		return event, fmt.Errorf("%w: missing account_id", webhook.ErrValidation)
+	}
+	if expected, ok := AccountFromContext(ctx); ok && expected != accountID {
+		return event, fmt.Errorf("%w: %w: %q", webhook.ErrValidation, ErrAccountMismatch, accountID)
+	}
+
+	t, err := p.provider.Tenant(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, ErrUnknownTenant) {
+			err = fmt.Errorf("%w: %w: %q", webhook.ErrValidation, err, accountID)
+		}
+		return event, err
+	}
+
+	d, err := p.begin(t, event.GetID(), p.cfg.Now())
+	if d == nil || err != nil {
+		return event, err
+	}
+
+	err = deliver(ctx, t, event, d)
+	p.finish(t.namespace(), event.GetID(), d, p.cfg.Now(), err == nil)
+	return event, err
+}
+
+// deliver runs the handlers that have not yet succeeded for this event and
+// writes the sink only once they all did, so a retry neither re-runs a
+// successful handler nor writes the event twice.
+func deliver(ctx context.Context, t *Tenant, event *base.BaseEvent, d *delivery) error {
+	if len(d.done) != len(t.Handlers) {
+		d.done = make([]bool, len(t.Handlers))
+	}
+
+	var errs []error
+	for i, handler := range t.Handlers {
+		if d.done[i] {
+			continue
+		}
+		if err := handler(ctx, event); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		d.done[i] = true
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	if t.Sink != nil {
+		return t.Sink.Write(ctx, event)
+	}
+	return nil
+}
+
+// HTTPHandler serves webhooks for all tenants. Each request is addressed to
+// one account (see Config.Account), must be signed with that tenant's
+// secret, and may only carry that account's events. Requests for an unknown
+// account fail with 404.
+func (p *Processor) HTTPHandler(opts ...webhook.HTTPOption) http.Handler {
+	opts = append(opts, webhook.WithHMACSignature(p.secret))
+	handler := webhook.NewHTTPHandler(p, opts...)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := WithAccount(r.Context(), p.cfg.Account(r))
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (p *Processor) secret(r *http.Request) ([]byte, error) {
+	accountID, _ := AccountFromContext(r.Context())
+	t, err := p.provider.Tenant(r.Context(), accountID)
+	if errors.Is(err, ErrUnknownTenant) {
+		return nil, fmt.Errorf("%w: %w: %q", webhook.ErrNotFound, err, accountID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(t.Secret), nil
+}
+
+// begin starts the delivery of id in the tenant's dedup namespace. It
+// returns a nil delivery for an ID already delivered within the dedup
+// window, and ErrInFlight while another delivery of id is running.
+// Duplicates are resolved before rate limiting so they use no tokens.
+func (p *Processor) begin(t *Tenant, id string, now time.Time) (*delivery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	w := p.seen[t.namespace()]
+	if w == nil {
+		w = &window{deliveries: make(map[string]*delivery)}
+		p.seen[t.namespace()] = w
+	}
+	w.expire(now.Add(-p.cfg.DedupWindow))
+
+	d := w.deliveries[id]
+	switch {
+	case d != nil && d.delivered:
+		return nil, nil
+	case d != nil && d.running:
+		return nil, fmt.Errorf("%w: %w: %q", webhook.ErrBackpressure, ErrInFlight, id)
+	case !p.allowLocked(t, now):
+		return nil, fmt.Errorf("%w: account %q rate limited", webhook.ErrBackpressure, t.AccountID)
+	}
+
+	if d == nil {
+		d = &delivery{}
+		w.deliveries[id] = d
+	}
+	d.running = true
+	return d, nil
+}
+
+// finish ends a delivery started by begin. A successful delivery marks id
+// as seen; a failed one keeps the handlers that succeeded for the retry.
+// Either is remembered for the dedup window.
+func (p *Processor) finish(namespace, id string, d *delivery, now time.Time, delivered bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	d.running = false
+	d.delivered = delivered
+	d.at = now
+	if w := p.seen[namespace]; w != nil {
+		w.queue = append(w.queue, entry{id: id, at: now})
+	}
+}
+
+func (p *Processor) allowLocked(t *Tenant, now time.Time) bool {
+	if t.RateLimit.Rate <= 0 {
+		return true
+	}
+
+	l := p.limiters[t.AccountID]
+	if l == nil || l.limit != t.RateLimit {
+		l = &limiter{limit: t.RateLimit, tokens: t.RateLimit.burst(), last: now}
+		p.limiters[t.AccountID] = l
+	}
+	return l.allow(now)
+}
+
+type limiter struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+func (l *limiter) allow(now time.Time) bool {
+	if elapsed := now.Sub(l.last).Seconds(); elapsed > 0 {
+		l.tokens = min(l.limit.burst(), l.tokens+elapsed*l.limit.Rate)
+		l.last = now
+	}
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+type delivery struct {
+	running   bool
+	delivered bool
+	// done marks the handlers that already succeeded for this event.
+	done []bool
+	at   time.Time
+}
+
+type entry struct {
+	id string
+	at time.Time
+}
+
+// window holds the deliveries of one dedup namespace. queue is in finish
+// order; an entry whose time no longer matches its delivery is stale.
+type window struct {
+	deliveries map[string]*delivery
+	queue      []entry
+}
+
+func (w *window) expire(cutoff time.Time) {
+	for len(w.queue) > 0 && !w.queue[0].at.After(cutoff) {
+		e := w.queue[0]
+		if d := w.deliveries[e.id]; d != nil && !d.running && d.at.Equal(e.at) {
+			delete(w.deliveries, e.id)
+		}
+		w.queue = w.queue[1:]
+	}
+}
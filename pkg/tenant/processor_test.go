@@ -0,0 +1,281 @@
+package tenant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/eventio"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/webhook"
+)
+
+func payload(t *testing.T, account, id string) []byte {
+	t.Helper()
+
+	for event, err := range eventio.ReadFile("../../test/events/dms-events/vision-drowsiness.json") {
+		if err != nil {
+			t.Fatalf("ReadFile() erro inesperado: %v", err)
+		}
+		event.ID = id
+		event.Attributes.Device.AccountID = account
+		data, err := json.Marshal(event)
+		if err != nil {
+			t.Fatalf("Marshal() erro inesperado: %v", err)
+		}
+		return data
+	}
+	t.Fatal("ReadFile() não retornou eventos")
+	return nil
+}
+
+type recorder struct {
+	events []*base.BaseEvent
+}
+
+func (r *recorder) handle(ctx context.Context, event *base.BaseEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *recorder) Write(ctx context.Context, event *base.BaseEvent) error {
+	return r.handle(ctx, event)
+}
+func (r *recorder) Flush(ctx context.Context) error { return nil }
+func (r *recorder) Close() error                    { return nil }
+
+func TestProcessor_IsolatesTenants(t *testing.T) {
+	var handledA, handledB, sinkA recorder
+	p := NewProcessor(NewStaticProvider(
+		&Tenant{AccountID: "account-a", Handlers: []dispatch.Handler{handledA.handle}, Sink: &sinkA},
+		&Tenant{AccountID: "account-b", Handlers: []dispatch.Handler{handledB.handle}},
+	), Config{})
+
+	ctx := context.Background()
+	if _, err := p.ProcessEvent(ctx, payload(t, "account-a", "1")); err != nil {
+		t.Fatalf("ProcessEvent() erro inesperado: %v", err)
+	}
+	if _, err := p.ProcessEvent(ctx, payload(t, "account-b", "2")); err != nil {
+		t.Fatalf("ProcessEvent() erro inesperado: %v", err)
+	}
+	if len(handledA.events) != 1 || handledA.events[0].GetID() != "1" || len(sinkA.events) != 1 {
+		t.Errorf("account-a recebeu %d eventos, sink %d, esperava 1 e 1", len(handledA.events), len(sinkA.events))
+	}
+	if len(handledB.events) != 1 || handledB.events[0].GetID() != "2" {
+		t.Errorf("account-b recebeu %d eventos, esperava 1", len(handledB.events))
+	}
+
+	_, err := p.ProcessEvent(WithAccount(ctx, "account-a"), payload(t, "account-b", "3"))
+	if !errors.Is(err, ErrAccountMismatch) || webhook.StatusCode(err) != http.StatusUnprocessableEntity {
+		t.Errorf("ProcessEvent() erro = %v, esperava ErrAccountMismatch com 422", err)
+	}
+	_, err = p.ProcessEvent(ctx, payload(t, "account-c", "4"))
+	if !errors.Is(err, ErrUnknownTenant) {
+		t.Errorf("ProcessEvent() erro = %v, esperava ErrUnknownTenant", err)
+	}
+	if len(handledA.events) != 1 || len(handledB.events) != 1 {
+		t.Errorf("eventos rejeitados chegaram aos handlers: a=%d b=%d", len(handledA.events), len(handledB.events))
+	}
+}
+
+func TestProcessor_DedupPerNamespace(t *testing.T) {
+	now := time.Date(2025, 12, 15, 19, 0, 0, 0, time.UTC)
+	fail := true
+	var handled recorder
+	p := NewProcessor(NewStaticProvider(
+		&Tenant{AccountID: "account-a", Handlers: []dispatch.Handler{handled.handle}},
+		&Tenant{AccountID: "account-b", Handlers: []dispatch.Handler{
+			handled.handle,
+			func(ctx context.Context, event *base.BaseEvent) error {
+				if fail {
+					return errors.New("falha")
+				}
+				return nil
+			},
+		}},
+	), Config{DedupWindow: time.Minute, Now: func() time.Time { return now }})
+
+	ctx := context.Background()
+	p.ProcessEvent(ctx, payload(t, "account-a", "1"))
+	p.ProcessEvent(ctx, payload(t, "account-a", "1"))
+	if len(handled.events) != 1 {
+		t.Fatalf("handler chamado %d vezes, esperava duplicata ignorada", len(handled.events))
+	}
+
+	if _, err := p.ProcessEvent(ctx, payload(t, "account-b", "1")); err == nil {
+		t.Fatal("ProcessEvent() esperava erro do handler")
+	}
+	fail = false
+	if _, err := p.ProcessEvent(ctx, payload(t, "account-b", "1")); err != nil {
+		t.Fatalf("ProcessEvent() erro inesperado na nova tentativa: %v", err)
+	}
+	if len(handled.events) != 2 {
+		t.Errorf("handler chamado %d vezes, esperava 2 (namespaces separados, sem repetir o handler que teve sucesso)", len(handled.events))
+	}
+
+	now = now.Add(2 * time.Minute)
+	p.ProcessEvent(ctx, payload(t, "account-a", "1"))
+	if len(handled.events) != 3 {
+		t.Errorf("handler chamado %d vezes, esperava reprocessar após a janela", len(handled.events))
+	}
+}
+
+func TestProcessor_RetryKeepsDedupWindowMoving(t *testing.T) {
+	now := time.Date(2025, 12, 15, 19, 0, 0, 0, time.UTC)
+	fail := true
+	var handled recorder
+	p := NewProcessor(NewStaticProvider(&Tenant{AccountID: "account-a", Handlers: []dispatch.Handler{
+		func(ctx context.Context, event *base.BaseEvent) error {
+			if fail && event.GetID() == "1" {
+				return errors.New("falha")
+			}
+			return handled.handle(ctx, event)
+		},
+	}}), Config{DedupWindow: time.Minute, Now: func() time.Time { return now }})
+
+	ctx := context.Background()
+	p.ProcessEvent(ctx, payload(t, "account-a", "1"))
+	now = now.Add(30 * time.Second)
+	p.ProcessEvent(ctx, payload(t, "account-a", "2"))
+	fail = false
+	now = now.Add(10 * time.Second)
+	p.ProcessEvent(ctx, payload(t, "account-a", "1"))
+
+	now = now.Add(55 * time.Second)
+	p.ProcessEvent(ctx, payload(t, "account-a", "2"))
+	if len(handled.events) != 3 {
+		t.Errorf("handler chamado %d vezes, esperava o evento 2 expirado após a nova tentativa do 1", len(handled.events))
+	}
+}
+
+func TestProcessor_RejectsConcurrentDuplicate(t *testing.T) {
+	ctx := context.Background()
+	var p *Processor
+	var nested error
+	p = NewProcessor(NewStaticProvider(&Tenant{AccountID: "account-a", Handlers: []dispatch.Handler{
+		func(ctx context.Context, event *base.BaseEvent) error {
+			if nested == nil {
+				_, nested = p.ProcessEvent(ctx, payload(t, "account-a", event.GetID()))
+			}
+			return errors.New("falha")
+		},
+	}}), Config{})
+
+	if _, err := p.ProcessEvent(ctx, payload(t, "account-a", "1")); err == nil {
+		t.Fatal("ProcessEvent() esperava erro do handler")
+	}
+	if !errors.Is(nested, ErrInFlight) || webhook.StatusCode(nested) != http.StatusTooManyRequests {
+		t.Errorf("duplicata concorrente: erro = %v, esperava ErrInFlight com 429", nested)
+	}
+}
+
+func TestProcessor_RateLimit(t *testing.T) {
+	now := time.Date(2025, 12, 15, 19, 0, 0, 0, time.UTC)
+	p := NewProcessor(NewStaticProvider(
+		&Tenant{AccountID: "account-a", RateLimit: RateLimit{Rate: 1, Burst: 2}},
+		&Tenant{AccountID: "account-b"},
+	), Config{Now: func() time.Time { return now }})
+
+	ctx := context.Background()
+	for i, id := range []string{"1", "2"} {
+		if _, err := p.ProcessEvent(ctx, payload(t, "account-a", id)); err != nil {
+			t.Fatalf("evento %d: erro inesperado: %v", i, err)
+		}
+	}
+	_, err := p.ProcessEvent(ctx, payload(t, "account-a", "3"))
+	if !errors.Is(err, webhook.ErrBackpressure) {
+		t.Errorf("ProcessEvent() erro = %v, esperava ErrBackpressure", err)
+	}
+	if _, err := p.ProcessEvent(ctx, payload(t, "account-b", "3")); err != nil {
+		t.Errorf("account-b limitada pela account-a: %v", err)
+	}
+
+	now = now.Add(time.Second)
+	if _, err := p.ProcessEvent(ctx, payload(t, "account-a", "3")); err != nil {
+		t.Errorf("ProcessEvent() erro = %v, esperava token reposto", err)
+	}
+	if _, err := p.ProcessEvent(ctx, payload(t, "account-a", "1")); err != nil {
+		t.Errorf("ProcessEvent() erro = %v, duplicata não deveria consumir token", err)
+	}
+}
+
+func TestProcessor_SinkWrittenAfterHandlers(t *testing.T) {
+	fail := true
+	var written recorder
+	p := NewProcessor(NewStaticProvider(&Tenant{
+		AccountID: "account-a",
+		Sink:      &written,
+		Handlers: []dispatch.Handler{func(ctx context.Context, event *base.BaseEvent) error {
+			if fail {
+				return errors.New("falha")
+			}
+			return nil
+		}},
+	}), Config{})
+
+	ctx := context.Background()
+	if _, err := p.ProcessEvent(ctx, payload(t, "account-a", "1")); err == nil {
+		t.Fatal("ProcessEvent() esperava erro do handler")
+	}
+	if len(written.events) != 0 {
+		t.Fatalf("sink recebeu %d eventos, esperava nenhum após falha do handler", len(written.events))
+	}
+
+	fail = false
+	if _, err := p.ProcessEvent(ctx, payload(t, "account-a", "1")); err != nil {
+		t.Fatalf("ProcessEvent() erro inesperado na nova tentativa: %v", err)
+	}
+	if len(written.events) != 1 {
+		t.Errorf("sink recebeu %d eventos, esperava 1", len(written.events))
+	}
+}
+
+func TestProcessor_HTTPHandler(t *testing.T) {
+	var handled recorder
+	p := NewProcessor(NewStaticProvider(
+		&Tenant{AccountID: "account-a", Secret: "segredo-a", Handlers: []dispatch.Handler{handled.handle}},
+		&Tenant{AccountID: "account-b", Secret: "segredo-b"},
+	), Config{})
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /webhooks/{account}", p.HTTPHandler())
+
+	send := func(account, secret string, body []byte) int {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/"+account, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(webhook.SignatureHeader, webhook.Sign([]byte(secret), body))
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	tests := []struct {
+		name    string
+		account string
+		secret  string
+		body    []byte
+		want    int
+	}{
+		{"assinado", "account-a", "segredo-a", payload(t, "account-a", "1"), http.StatusAccepted},
+		{"segredo de outro tenant", "account-a", "segredo-b", payload(t, "account-a", "2"), http.StatusUnauthorized},
+		{"conta desconhecida", "account-c", "segredo-a", payload(t, "account-c", "3"), http.StatusNotFound},
+		{"evento de outra conta", "account-b", "segredo-b", payload(t, "account-a", "4"), http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := send(tt.account, tt.secret, tt.body); got != tt.want {
+				t.Errorf("status = %d, esperava %d", got, tt.want)
+			}
+		})
+	}
+
+	if len(handled.events) != 1 || handled.events[0].GetID() != "1" {
+		t.Errorf("account-a recebeu %d eventos, esperava apenas o evento assinado", len(handled.events))
+	}
+}
@@ -0,0 +1,87 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/sink"
+)
+
+var (
+	ErrUnknownTenant   = errors.New("tenant: unknown account")
+	ErrAccountMismatch = errors.New("tenant: event belongs to another account")
+	ErrInvalidConfig   = errors.New("tenant: invalid config")
+	ErrInFlight        = errors.New("tenant: event is already being delivered")
+)
+
+// Tenant is the per-account configuration. Events are only ever delivered
+// to the Sink and Handlers of the tenant whose AccountID matches
+// attributes.device.account_id.
+type Tenant struct {
+	AccountID string
+	Secret    string
+	Handlers  []dispatch.Handler
+	Sink      sink.EventSink
+	RateLimit RateLimit
+	// DedupNamespace scopes duplicate detection. It defaults to AccountID;
+	// tenants sharing a namespace share their seen event IDs.
+	DedupNamespace string
+}
+
+func (t *Tenant) namespace() string {
+	if t.DedupNamespace != "" {
+		return t.DedupNamespace
+	}
+	return t.AccountID
+}
+
+// RateLimit is a token bucket refilled at Rate events per second. A zero
+// Rate disables limiting; Burst defaults to Rate rounded up.
+type RateLimit struct {
+	Rate  float64 `yaml:"rate"`
+	Burst int     `yaml:"burst,omitempty"`
+}
+
+func (l RateLimit) burst() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return math.Max(1, math.Ceil(l.Rate))
+}
+
+type Provider interface {
+	Tenant(ctx context.Context, accountID string) (*Tenant, error)
+}
+
+// StaticProvider serves a fixed set of tenants keyed by account ID.
+type StaticProvider map[string]*Tenant
+
+func NewStaticProvider(tenants ...*Tenant) StaticProvider {
+	p := make(StaticProvider, len(tenants))
+	for _, t := range tenants {
+		p[t.AccountID] = t
+	}
+	return p
+}
+
+func (p StaticProvider) Tenant(ctx context.Context, accountID string) (*Tenant, error) {
+	if t, ok := p[accountID]; ok && accountID != "" {
+		return t, nil
+	}
+	return nil, ErrUnknownTenant
+}
+
+type accountKey struct{}
+
+// WithAccount binds ctx to a single account. Processor rejects events from
+// any other account processed under this context.
+func WithAccount(ctx context.Context, accountID string) context.Context {
+	return context.WithValue(ctx, accountKey{}, accountID)
+}
+
+func AccountFromContext(ctx context.Context) (string, bool) {
+	accountID, ok := ctx.Value(accountKey{}).(string)
+	return accountID, ok
+}
@@ -0,0 +1,328 @@
+package trip
+
+import (
+	"context"
+	"iter"
+	"sort"
+	"sync"
+	"time"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/common"
+	"go-eventlib/pkg/types/telemetry"
+)
+
+const (
+	defaultInactivityTimeout = 30 * time.Minute
+	defaultClosingGrace      = time.Minute
+)
+
+type CloseReason string
+
+const (
+	CloseReasonIgnitionOff CloseReason = "ignition_off"
+	CloseReasonInactivity  CloseReason = "inactivity"
+	CloseReasonShutdown    CloseReason = "shutdown"
+)
+
+type Point struct {
+	Time        time.Time
+	Coordinates common.Coordinates
+}
+
+type Trip struct {
+	ID          string
+	DeviceID    string
+	AccountID   string
+	StartedAt   time.Time
+	EndedAt     time.Time
+	IgnitionOn  bool
+	IgnitionOff bool
+	CloseReason CloseReason
+	Timeline    []*base.BaseEvent
+	Track       []Point
+
+	lastEventAt time.Time
+	seen        map[string]bool
+}
+
+func (t *Trip) Duration() time.Duration {
+	return t.EndedAt.Sub(t.StartedAt)
+}
+
+func (t *Trip) Complete() bool {
+	return t.IgnitionOn && t.IgnitionOff
+}
+
+func (t *Trip) add(event *base.BaseEvent) bool {
+	key := event.GetID()
+	if key != "" && t.seen[key] {
+		return false
+	}
+	t.seen[key] = true
+
+	if t.DeviceID == "" {
+		t.DeviceID = event.GetDeviceID()
+	}
+	if t.AccountID == "" {
+		t.AccountID = event.GetAccountID()
+	}
+
+	createdAt := event.GetCreatedAt()
+	i := sort.Search(len(t.Timeline), func(i int) bool {
+		return t.Timeline[i].GetCreatedAt().After(createdAt)
+	})
+	t.Timeline = append(t.Timeline, nil)
+	copy(t.Timeline[i+1:], t.Timeline[i:])
+	t.Timeline[i] = event
+
+	if createdAt.After(t.lastEventAt) {
+		t.lastEventAt = createdAt
+	}
+
+	switch IgnitionStatus(event) {
+	case telemetry.IgnitionStatusOn:
+		if !t.IgnitionOn || createdAt.Before(t.StartedAt) {
+			t.StartedAt = createdAt
+		}
+		t.IgnitionOn = true
+	case telemetry.IgnitionStatusOff:
+		if !t.IgnitionOff || createdAt.After(t.EndedAt) {
+			t.EndedAt = createdAt
+		}
+		t.IgnitionOff = true
+	}
+
+	if !t.IgnitionOn {
+		t.StartedAt = t.Timeline[0].GetCreatedAt()
+	}
+	if !t.IgnitionOff {
+		t.EndedAt = t.lastEventAt
+	}
+
+	if coords := event.GetCoordinates(); coords != nil && (coords.Latitude != 0 || coords.Longitude != 0) {
+		point := Point{Time: createdAt, Coordinates: *coords}
+		j := sort.Search(len(t.Track), func(j int) bool {
+			return t.Track[j].Time.After(createdAt)
+		})
+		t.Track = append(t.Track, Point{})
+		copy(t.Track[j+1:], t.Track[j:])
+		t.Track[j] = point
+	}
+
+	return true
+}
+
+func IgnitionStatus(event *base.BaseEvent) telemetry.IgnitionStatus {
+	if event.GetEventName() != "IGNITION" {
+		return ""
+	}
+	return telemetry.New(event).GetIgnitionStatus()
+}
+
+type Config struct {
+	InactivityTimeout time.Duration
+	ClosingGrace      time.Duration
+	OnClose           func(trip *Trip)
+}
+
+type Reconstructor struct {
+	cfg Config
+
+	mu      sync.Mutex
+	devices map[string]*deviceTrips
+}
+
+// deviceTrips holds the trips of one device. Devices upload on their own
+// schedule, so time advances per device with its latest event and one
+// device's clock never closes another's trips.
+type deviceTrips struct {
+	watermark time.Time
+	open      map[string]*Trip
+	// closed remembers the last event time of closed trips, so late events
+	// are dropped instead of reopening them.
+	closed map[string]time.Time
+}
+
+func NewReconstructor(cfg Config) *Reconstructor {
+	if cfg.InactivityTimeout <= 0 {
+		cfg.InactivityTimeout = defaultInactivityTimeout
+	}
+	if cfg.ClosingGrace <= 0 {
+		cfg.ClosingGrace = defaultClosingGrace
+	}
+
+	return &Reconstructor{
+		cfg:     cfg,
+		devices: make(map[string]*deviceTrips),
+	}
+}
+
+func (r *Reconstructor) Add(event *base.BaseEvent) {
+	id := event.GetTripID()
+	if id == "" {
+		return
+	}
+
+	r.mu.Lock()
+	dev, ok := r.devices[event.GetDeviceID()]
+	if !ok {
+		dev = &deviceTrips{open: make(map[string]*Trip), closed: make(map[string]time.Time)}
+		r.devices[event.GetDeviceID()] = dev
+	}
+	if _, ok := dev.closed[id]; ok {
+		r.mu.Unlock()
+		return
+	}
+
+	t, ok := dev.open[id]
+	if !ok {
+		t = &Trip{ID: id, seen: make(map[string]bool)}
+		dev.open[id] = t
+	}
+	t.add(event)
+
+	if event.GetCreatedAt().After(dev.watermark) {
+		dev.watermark = event.GetCreatedAt()
+	}
+	closed := r.expireLocked(dev, dev.watermark)
+	sortTrips(closed)
+	r.mu.Unlock()
+
+	r.emit(closed)
+}
+
+// Expire closes the trips of every device that are idle at now, normally
+// the wall clock, so trips of devices that stopped sending still close.
+func (r *Reconstructor) Expire(now time.Time) []*Trip {
+	r.mu.Lock()
+	var closed []*Trip
+	for deviceID, dev := range r.devices {
+		closed = append(closed, r.expireLocked(dev, now)...)
+		if len(dev.open) == 0 && len(dev.closed) == 0 {
+			delete(r.devices, deviceID)
+		}
+	}
+	sortTrips(closed)
+	r.mu.Unlock()
+
+	r.emit(closed)
+	return closed
+}
+
+func (r *Reconstructor) Close() []*Trip {
+	r.mu.Lock()
+	var closed []*Trip
+	for _, dev := range r.devices {
+		for id, t := range dev.open {
+			t.CloseReason = CloseReasonShutdown
+			if t.IgnitionOff {
+				t.CloseReason = CloseReasonIgnitionOff
+			}
+			closed = append(closed, t)
+			delete(dev.open, id)
+			dev.closed[id] = t.lastEventAt
+		}
+	}
+	r.mu.Unlock()
+
+	sortTrips(closed)
+	r.emit(closed)
+	return closed
+}
+
+// Open returns a snapshot of the open trips. The trips are copies, so they
+// do not change as further events arrive.
+func (r *Reconstructor) Open() []*Trip {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var open []*Trip
+	for _, dev := range r.devices {
+		for _, t := range dev.open {
+			snapshot := *t
+			snapshot.Timeline = append([]*base.BaseEvent(nil), t.Timeline...)
+			snapshot.Track = append([]Point(nil), t.Track...)
+			snapshot.seen = nil
+			open = append(open, &snapshot)
+		}
+	}
+	sortTrips(open)
+	return open
+}
+
+func (r *Reconstructor) Handler() dispatch.Handler {
+	return func(ctx context.Context, event *base.BaseEvent) error {
+		r.Add(event)
+		return nil
+	}
+}
+
+// expireLocked closes the trips of dev that are idle at now.
+func (r *Reconstructor) expireLocked(dev *deviceTrips, now time.Time) []*Trip {
+	var closed []*Trip
+	for id, t := range dev.open {
+		idle := now.Sub(t.lastEventAt)
+		switch {
+		case t.IgnitionOff && idle >= r.cfg.ClosingGrace:
+			t.CloseReason = CloseReasonIgnitionOff
+		case idle >= r.cfg.InactivityTimeout:
+			t.CloseReason = CloseReasonInactivity
+		default:
+			continue
+		}
+		closed = append(closed, t)
+		delete(dev.open, id)
+		dev.closed[id] = t.lastEventAt
+	}
+
+	// Closed trip IDs are forgotten after twice the inactivity timeout.
+	for id, lastEventAt := range dev.closed {
+		if now.Sub(lastEventAt) >= 2*r.cfg.InactivityTimeout {
+			delete(dev.closed, id)
+		}
+	}
+	return closed
+}
+
+func (r *Reconstructor) emit(trips []*Trip) {
+	if r.cfg.OnClose == nil {
+		return
+	}
+	for _, t := range trips {
+		r.cfg.OnClose(t)
+	}
+}
+
+func Collect(events iter.Seq2[*base.BaseEvent, error], cfg Config) ([]*Trip, error) {
+	var trips []*Trip
+	onClose := cfg.OnClose
+	cfg.OnClose = func(t *Trip) {
+		trips = append(trips, t)
+		if onClose != nil {
+			onClose(t)
+		}
+	}
+
+	r := NewReconstructor(cfg)
+	for event, err := range events {
+		if err != nil {
+			return trips, err
+		}
+		r.Add(event)
+	}
+	r.Close()
+
+	sortTrips(trips)
+	return trips, nil
+}
+
+func sortTrips(trips []*Trip) {
+	sort.Slice(trips, func(i, j int) bool {
+		if !trips[i].StartedAt.Equal(trips[j].StartedAt) {
+			return trips[i].StartedAt.Before(trips[j].StartedAt)
+		}
+		return trips[i].ID < trips[j].ID
+	})
+}
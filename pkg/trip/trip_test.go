@@ -0,0 +1,195 @@
+package trip
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"go-eventlib/pkg/eventio"
+	"go-eventlib/pkg/types/base"
+)
+
+var start = time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+
+func tripEvent(id, trip string, offset time.Duration, group, name string, detail map[string]interface{}) *base.BaseEvent {
+	return &base.BaseEvent{
+		ID:        id,
+		CreatedAt: start.Add(offset),
+		Category:  "EVENT_CATEGORY_" + base.EventCategory(group),
+		Attributes: base.Attributes{
+			Device: &base.Device{ID: "device-1", AccountID: "account-1"},
+			Data: &base.Data{TripEvent: map[string]interface{}{
+				"trip_id": trip,
+				"telemetry": map[string]interface{}{
+					"event_name": name,
+					"detail":     detail,
+				},
+			}},
+		},
+	}
+}
+
+func ignition(id, trip string, offset time.Duration, status string) *base.BaseEvent {
+	event := tripEvent(id, trip, offset, "VEHICLE", "IGNITION", nil)
+	event.Attributes.Data.TripEvent = map[string]interface{}{
+		"trip_id": trip,
+		"telemetry": map[string]interface{}{
+			"event_name": "IGNITION",
+			"ignition": map[string]interface{}{
+				"status": status,
+				"location": map[string]interface{}{
+					"coordinates": map[string]interface{}{"latitude": -23.5, "longitude": -46.6},
+				},
+			},
+		},
+	}
+	return event
+}
+
+func located(id, trip string, offset time.Duration, lat, lon float64) *base.BaseEvent {
+	return tripEvent(id, trip, offset, "DMS", "DROWSINESS", map[string]interface{}{
+		"location": map[string]interface{}{
+			"coordinates": map[string]interface{}{"latitude": lat, "longitude": lon},
+		},
+	})
+}
+
+func timelineIDs(trip *Trip) string {
+	var ids []string
+	for _, event := range trip.Timeline {
+		ids = append(ids, event.GetID())
+	}
+	return fmt.Sprint(ids)
+}
+
+func TestReconstructor_OutOfOrderTrip(t *testing.T) {
+	var closed []*Trip
+	r := NewReconstructor(Config{OnClose: func(trip *Trip) { closed = append(closed, trip) }})
+
+	r.Add(located("dms-2", "trip-1", 20*time.Minute, -23.6, -46.7))
+	r.Add(ignition("on", "trip-1", 0, "IGNITION_STATUS_ON"))
+	r.Add(located("dms-1", "trip-1", 10*time.Minute, -23.55, -46.65))
+	r.Add(located("dms-1", "trip-1", 10*time.Minute, -23.55, -46.65))
+	r.Add(ignition("off", "trip-1", 30*time.Minute, "IGNITION_STATUS_OFF"))
+
+	if len(closed) != 0 {
+		t.Fatalf("viagem fechada antes do período de tolerância: %d", len(closed))
+	}
+	if got := r.Expire(start.Add(40 * time.Minute)); len(got) != 1 {
+		t.Fatalf("Expire() fechou %d viagens, esperava 1", len(got))
+	}
+
+	trip := closed[0]
+	if got := timelineIDs(trip); got != "[on dms-1 dms-2 off]" {
+		t.Errorf("Timeline = %s, esperava [on dms-1 dms-2 off]", got)
+	}
+	if !trip.StartedAt.Equal(start) || trip.Duration() != 30*time.Minute {
+		t.Errorf("StartedAt = %v, Duration() = %v, esperava %v e 30m", trip.StartedAt, trip.Duration(), start)
+	}
+	if !trip.Complete() || trip.CloseReason != CloseReasonIgnitionOff {
+		t.Errorf("Complete() = %v, CloseReason = %s, esperava true e %s", trip.Complete(), trip.CloseReason, CloseReasonIgnitionOff)
+	}
+	if trip.DeviceID != "device-1" || trip.AccountID != "account-1" {
+		t.Errorf("DeviceID = %s, AccountID = %s", trip.DeviceID, trip.AccountID)
+	}
+	if len(trip.Track) != 4 || trip.Track[1].Coordinates.Latitude != -23.55 {
+		t.Errorf("Track = %+v, esperava 4 pontos ordenados", trip.Track)
+	}
+
+	r.Add(located("late", "trip-1", 31*time.Minute, -23.6, -46.7))
+	if open := r.Open(); len(open) != 0 {
+		t.Errorf("evento atrasado reabriu a viagem: %d abertas", len(open))
+	}
+}
+
+func TestReconstructor_InactivityTimeout(t *testing.T) {
+	var closed []*Trip
+	r := NewReconstructor(Config{
+		InactivityTimeout: 10 * time.Minute,
+		OnClose:           func(trip *Trip) { closed = append(closed, trip) },
+	})
+
+	r.Add(located("a-1", "trip-a", 0, -23.5, -46.6))
+	r.Add(located("b-1", "trip-b", 5*time.Minute, -23.5, -46.6))
+	r.Add(located("b-2", "trip-b", 15*time.Minute, -23.5, -46.6))
+
+	if len(closed) != 1 || closed[0].ID != "trip-a" {
+		t.Fatalf("fechadas = %d, esperava trip-a fechada por inatividade", len(closed))
+	}
+	if closed[0].CloseReason != CloseReasonInactivity || closed[0].Complete() {
+		t.Errorf("CloseReason = %s, esperava %s", closed[0].CloseReason, CloseReasonInactivity)
+	}
+
+	remaining := r.Close()
+	if len(remaining) != 1 || remaining[0].ID != "trip-b" || remaining[0].CloseReason != CloseReasonShutdown {
+		t.Errorf("Close() = %+v, esperava trip-b com %s", remaining, CloseReasonShutdown)
+	}
+	if remaining[0].Duration() != 10*time.Minute {
+		t.Errorf("Duration() = %v, esperava 10m", remaining[0].Duration())
+	}
+}
+
+func TestReconstructor_WatermarkIsPerDevice(t *testing.T) {
+	r := NewReconstructor(Config{InactivityTimeout: 10 * time.Minute})
+
+	r.Add(located("a-1", "trip-a", 0, -23.5, -46.6))
+
+	// Another device uploading an hour ahead must not close device-1's trip.
+	other := located("b-1", "trip-b", time.Hour, -23.5, -46.6)
+	other.Attributes.Device = &base.Device{ID: "device-2", AccountID: "account-1"}
+	r.Add(other)
+
+	if open := r.Open(); len(open) != 2 {
+		t.Fatalf("Open() = %d, esperava 2 viagens abertas", len(open))
+	}
+
+	closed := r.Expire(start.Add(time.Hour))
+	if len(closed) != 1 || closed[0].ID != "trip-a" || closed[0].CloseReason != CloseReasonInactivity {
+		t.Errorf("Expire() = %+v, esperava trip-a fechada por inatividade", closed)
+	}
+}
+
+func TestReconstructor_OpenReturnsSnapshots(t *testing.T) {
+	r := NewReconstructor(Config{})
+	r.Add(located("e-1", "trip-1", 0, -23.5, -46.6))
+
+	open := r.Open()
+	r.Add(located("e-2", "trip-1", time.Minute, -23.6, -46.7))
+
+	if len(open) != 1 || len(open[0].Timeline) != 1 || len(open[0].Track) != 1 {
+		t.Errorf("Open() = %+v, snapshot não deveria mudar com novos eventos", open)
+	}
+	if again := r.Open(); len(again[0].Timeline) != 2 {
+		t.Errorf("Open() timeline = %d, esperava 2", len(again[0].Timeline))
+	}
+}
+
+func TestReconstructor_IgnoresStandaloneEvents(t *testing.T) {
+	r := NewReconstructor(Config{})
+	r.Add(&base.BaseEvent{ID: "standalone", CreatedAt: start})
+
+	if open := r.Open(); len(open) != 0 {
+		t.Errorf("Open() = %d, esperava 0", len(open))
+	}
+}
+
+func TestCollect_Fixtures(t *testing.T) {
+	trips, err := Collect(eventio.ReadDir(os.DirFS("../../test/events"), "**/*.json"), Config{})
+	if err != nil {
+		t.Fatalf("Collect() erro inesperado: %v", err)
+	}
+
+	var found *Trip
+	for _, trip := range trips {
+		if trip.ID == "trip-001" {
+			found = trip
+		}
+	}
+	if found == nil {
+		t.Fatal("trip-001 não reconstruída a partir dos fixtures")
+	}
+	if !found.IgnitionOn || found.DeviceID != "device-123" || len(found.Track) != 1 {
+		t.Errorf("trip-001 = %+v, esperava ignição ligada em device-123 com 1 ponto", found)
+	}
+}
@@ -1,7 +1,13 @@
 package base
 
 import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
 	"time"
+
+	"go-eventlib/pkg/types/common"
 )
 
 type EventStatus string
@@ -77,3 +83,122 @@ type Data struct {
 	StandaloneEvent interface{} `json:"standalone_event,omitempty"`
 	TripEvent       interface{} `json:"trip_event,omitempty"`
 }
+
+func (e *BaseEvent) GetAccountID() string {
+	if e.Attributes.Device != nil {
+		return e.Attributes.Device.AccountID
+	}
+	return ""
+}
+
+func (e *BaseEvent) GetTripID() string {
+	if e.Attributes.Data == nil || e.Attributes.Data.TripEvent == nil {
+		return ""
+	}
+
+	var tripEvent struct {
+		TripID string `json:"trip_id"`
+	}
+	if !remarshal(e.Attributes.Data.TripEvent, &tripEvent) {
+		return ""
+	}
+
+	return tripEvent.TripID
+}
+
+func (e *BaseEvent) GetEventName() string {
+	if group := e.GetEventGroup(); group != nil {
+		if name, ok := group["event_name"].(string); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+func (e *BaseEvent) GetCoordinates() *common.Coordinates {
+	group := e.GetEventGroup()
+	for _, key := range slices.Sorted(maps.Keys(group)) {
+		detail, ok := group[key].(map[string]interface{})
+		if !ok || detail["location"] == nil {
+			continue
+		}
+
+		var location struct {
+			Coordinates *common.Coordinates `json:"coordinates"`
+		}
+		if remarshal(detail["location"], &location) && location.Coordinates != nil {
+			return location.Coordinates
+		}
+	}
+	return nil
+}
+
+func (e *BaseEvent) GetFixTime() (time.Time, bool) {
+	group := e.GetEventGroup()
+	for _, key := range slices.Sorted(maps.Keys(group)) {
+		detail, ok := group[key].(map[string]interface{})
+		if !ok || detail["location"] == nil {
+			continue
+		}
+
+		var location struct {
+			Fix *struct {
+				Timestamp json.Number `json:"timestamp"`
+			} `json:"fix"`
+		}
+		if !remarshal(detail["location"], &location) || location.Fix == nil {
+			continue
+		}
+		if millis, err := location.Fix.Timestamp.Int64(); err == nil && millis > 0 {
+			return time.UnixMilli(millis).UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// GetEventGroup returns the detail object of the trip or standalone payload,
+// e.g. trip_event.dms for event_group_name "DMS". Payloads without a usable
+// event_group_name fall back to the first object-valued key in key order.
+func (e *BaseEvent) GetEventGroup() map[string]interface{} {
+	if e.Attributes.Data == nil {
+		return nil
+	}
+
+	payload := e.Attributes.Data.TripEvent
+	if payload == nil {
+		payload = e.Attributes.Data.StandaloneEvent
+	}
+	if payload == nil {
+		return nil
+	}
+
+	var event map[string]interface{}
+	if !remarshal(payload, &event) {
+		return nil
+	}
+
+	if name, ok := event["event_group_name"].(string); ok {
+		if group, ok := event[strings.ToLower(name)].(map[string]interface{}); ok {
+			return group
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(event)) {
+		if key == "trip_id" || key == "event_group_name" {
+			continue
+		}
+		if group, ok := event[key].(map[string]interface{}); ok {
+			return group
+		}
+	}
+
+	return nil
+}
+
+func remarshal(in interface{}, out interface{}) bool {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, out) == nil
+}
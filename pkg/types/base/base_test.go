@@ -71,3 +71,143 @@ func TestBaseEvent_GetCreatedAt(t *testing.T) {
 		t.Errorf("GetCreatedAt() = %v, esperava %v", got, now)
 	}
 }
+
+func TestBaseEvent_GetAccountID(t *testing.T) {
+	event := &BaseEvent{
+		Attributes: Attributes{
+			Device: &Device{ID: "device-123", AccountID: "account-123"},
+		},
+	}
+
+	if got := event.GetAccountID(); got != "account-123" {
+		t.Errorf("GetAccountID() = %s, esperava account-123", got)
+	}
+
+	if got := (&BaseEvent{}).GetAccountID(); got != "" {
+		t.Errorf("GetAccountID() = %s, esperava string vazia", got)
+	}
+}
+
+func TestBaseEvent_GetTripIDAndEventName(t *testing.T) {
+	event := &BaseEvent{
+		Attributes: Attributes{
+			Data: &Data{
+				TripEvent: map[string]interface{}{
+					"trip_id":          "trip-123",
+					"event_group_name": "DMS",
+					"dms": map[string]interface{}{
+						"id":         "dms-123",
+						"event_name": "DROWSINESS",
+					},
+				},
+			},
+		},
+	}
+
+	if got := event.GetTripID(); got != "trip-123" {
+		t.Errorf("GetTripID() = %s, esperava trip-123", got)
+	}
+	if got := event.GetEventName(); got != "DROWSINESS" {
+		t.Errorf("GetEventName() = %s, esperava DROWSINESS", got)
+	}
+}
+
+func TestBaseEvent_GetEventName_Standalone(t *testing.T) {
+	event := &BaseEvent{
+		Attributes: Attributes{
+			Data: &Data{
+				StandaloneEvent: map[string]interface{}{
+					"event_group_name": "CONNECTION",
+					"connection": map[string]interface{}{
+						"event_name": "WIFI_CONNECTED",
+					},
+				},
+			},
+		},
+	}
+
+	if got := event.GetEventName(); got != "WIFI_CONNECTED" {
+		t.Errorf("GetEventName() = %s, esperava WIFI_CONNECTED", got)
+	}
+	if got := event.GetTripID(); got != "" {
+		t.Errorf("GetTripID() = %s, esperava string vazia", got)
+	}
+	if got := (&BaseEvent{}).GetEventName(); got != "" {
+		t.Errorf("GetEventName() = %s, esperava string vazia", got)
+	}
+}
+
+func TestBaseEvent_GetEventGroup_UsesEventGroupName(t *testing.T) {
+	event := &BaseEvent{
+		Attributes: Attributes{
+			Data: &Data{
+				TripEvent: map[string]interface{}{
+					"trip_id":          "trip-123",
+					"event_group_name": "TELEMETRY",
+					"dms":              map[string]interface{}{"event_name": "DROWSINESS"},
+					"metadata":         map[string]interface{}{"event_name": "OTHER"},
+					"telemetry":        map[string]interface{}{"event_name": "IGNITION"},
+				},
+			},
+		},
+	}
+
+	for i := 0; i < 20; i++ {
+		if got := event.GetEventName(); got != "IGNITION" {
+			t.Fatalf("GetEventName() = %s, esperava IGNITION (grupo de event_group_name)", got)
+		}
+	}
+
+	event.Attributes.Data.TripEvent.(map[string]interface{})["event_group_name"] = ""
+	for i := 0; i < 20; i++ {
+		if got := event.GetEventName(); got != "DROWSINESS" {
+			t.Fatalf("GetEventName() = %s, esperava DROWSINESS (primeira chave em ordem)", got)
+		}
+	}
+}
+
+func TestBaseEvent_GetCoordinates(t *testing.T) {
+	event := &BaseEvent{
+		Attributes: Attributes{
+			Data: &Data{
+				TripEvent: map[string]interface{}{
+					"trip_id":          "trip-123",
+					"event_group_name": "TELEMETRY",
+					"telemetry": map[string]interface{}{
+						"event_name": "IGNITION",
+						"ignition": map[string]interface{}{
+							"status": "IGNITION_STATUS_ON",
+							"location": map[string]interface{}{
+								"coordinates": map[string]interface{}{
+									"latitude":  -23.55052,
+									"longitude": -46.633308,
+									"speed":     42.5,
+								},
+								"fix": map[string]interface{}{"timestamp": "1765824528277"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got := event.GetCoordinates()
+	if got == nil {
+		t.Fatal("GetCoordinates() = nil, esperava coordenadas")
+	}
+	if got.Latitude != -23.55052 || got.Longitude != -46.633308 || got.Speed != 42.5 {
+		t.Errorf("GetCoordinates() = %+v, esperava lat -23.55052, lon -46.633308, speed 42.5", got)
+	}
+	if got := (&BaseEvent{}).GetCoordinates(); got != nil {
+		t.Errorf("GetCoordinates() = %+v, esperava nil", got)
+	}
+
+	fix, ok := event.GetFixTime()
+	if !ok || !fix.Equal(time.UnixMilli(1765824528277)) {
+		t.Errorf("GetFixTime() = %v, %v, esperava %v", fix, ok, time.UnixMilli(1765824528277).UTC())
+	}
+	if _, ok := (&BaseEvent{}).GetFixTime(); ok {
+		t.Error("GetFixTime() em evento vazio retornou ok")
+	}
+}
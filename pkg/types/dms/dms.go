@@ -2,6 +2,7 @@ package dms
 
 import (
 	"encoding/json"
+	"strconv"
 	"time"
 
 	"go-eventlib/pkg/types/base"
@@ -84,3 +85,51 @@ func (e *Event) GetEventName() string {
 	}
 	return ""
 }
+
+func (e *Event) GetDetection() map[string]interface{} {
+	if e.Attributes.Data == nil || e.Attributes.Data.TripEvent == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(e.Attributes.Data.TripEvent)
+	if err != nil {
+		return nil
+	}
+
+	var tripEvent struct {
+		DMS map[string]interface{} `json:"dms"`
+	}
+	if err := json.Unmarshal(data, &tripEvent); err != nil {
+		return nil
+	}
+
+	for _, value := range tripEvent.DMS {
+		if detection, ok := value.(map[string]interface{}); ok {
+			return detection
+		}
+	}
+	return nil
+}
+
+func (e *Event) GetConfidence() (float64, bool) {
+	confidence, ok := e.GetDetection()["confidence"].(float64)
+	return confidence, ok
+}
+
+func (e *Event) GetAttribute(name string) (string, bool) {
+	attributes, ok := e.GetDetection()["attributes"].(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	value, ok := attributes[name].(string)
+	return value, ok
+}
+
+func (e *Event) GetFloatAttribute(name string) (float64, bool) {
+	value, ok := e.GetAttribute(name)
+	if !ok {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	return f, err == nil
+}
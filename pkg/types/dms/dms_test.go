@@ -267,3 +267,41 @@ func TestDMSEvent_GetDMSData_InvalidJSON(t *testing.T) {
 		t.Error("GetDMSData() retornou DMSEventData, esperava nil para JSON inválido")
 	}
 }
+
+func TestDMSEvent_GetDetectionAttributes(t *testing.T) {
+	event := New(&base.BaseEvent{
+		Attributes: base.Attributes{Data: &base.Data{
+			TripEvent: map[string]interface{}{
+				"trip_id": "trip-123",
+				"dms": map[string]interface{}{
+					"event_name": "DROWSINESS",
+					"drowsiness": map[string]interface{}{
+						"name":       "DROWSINESS",
+						"confidence": 0.9,
+						"attributes": map[string]interface{}{
+							"perclos":        "0.25",
+							"blinks_per_min": "8",
+							"invalid":        "n/a",
+						},
+					},
+				},
+			},
+		}},
+	})
+
+	if got, ok := event.GetConfidence(); !ok || got != 0.9 {
+		t.Errorf("GetConfidence() = %f, %v, esperava 0.9, true", got, ok)
+	}
+	if got, ok := event.GetAttribute("perclos"); !ok || got != "0.25" {
+		t.Errorf("GetAttribute(perclos) = %s, %v, esperava 0.25, true", got, ok)
+	}
+	if got, ok := event.GetFloatAttribute("blinks_per_min"); !ok || got != 8 {
+		t.Errorf("GetFloatAttribute(blinks_per_min) = %f, %v, esperava 8, true", got, ok)
+	}
+	if _, ok := event.GetFloatAttribute("invalid"); ok {
+		t.Error("GetFloatAttribute(invalid) = true, esperava false")
+	}
+	if _, ok := New(&base.BaseEvent{}).GetConfidence(); ok {
+		t.Error("GetConfidence() = true, esperava false sem dados")
+	}
+}
@@ -44,6 +44,12 @@ type IgnitionEvent struct {
 	Location interface{} `json:"location,omitempty"`
 }
 
+type BatteryEvent struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Component string `json:"component"`
+}
+
 type Event struct {
 	*base.BaseEvent
 }
@@ -76,3 +82,103 @@ func (e *Event) GetBatteryMetrics() map[string]*BatteryMetric {
 	}
 	return nil
 }
+
+func (e *Event) GetOdometer() (float64, bool) {
+	if e.Attributes.Data == nil || e.Attributes.Data.Telemetry == nil {
+		return 0, false
+	}
+
+	data, err := json.Marshal(e.Attributes.Data.Telemetry)
+	if err != nil {
+		return 0, false
+	}
+
+	var telemetry struct {
+		Metrics struct {
+			Odometer *struct {
+				Value float64 `json:"value"`
+			} `json:"odometer"`
+		} `json:"metrics"`
+	}
+	if err := json.Unmarshal(data, &telemetry); err != nil || telemetry.Metrics.Odometer == nil {
+		return 0, false
+	}
+
+	return telemetry.Metrics.Odometer.Value, true
+}
+
+func (e *Event) GetTripTelemetry() *TripTelemetry {
+	if e.Attributes.Data == nil || e.Attributes.Data.TripEvent == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(e.Attributes.Data.TripEvent)
+	if err != nil {
+		return nil
+	}
+
+	var tripEvent struct {
+		Telemetry *TripTelemetry `json:"telemetry"`
+	}
+	if err := json.Unmarshal(data, &tripEvent); err != nil {
+		return nil
+	}
+
+	return tripEvent.Telemetry
+}
+
+func (e *Event) GetIgnitionStatus() IgnitionStatus {
+	if trip := e.GetTripTelemetry(); trip != nil && trip.Ignition != nil {
+		return IgnitionStatus(trip.Ignition.Status)
+	}
+	return ""
+}
+
+// GetCurrentIgnitionStatus returns the ignition status reported by a trip
+// ignition event, falling back to the status in periodic telemetry.
+func (e *Event) GetCurrentIgnitionStatus() IgnitionStatus {
+	if ignition := e.GetIgnitionStatus(); ignition != "" {
+		return ignition
+	}
+	if data := e.GetTelemetryData(); data != nil {
+		return data.Status
+	}
+	return ""
+}
+
+func (e *Event) IsVehicleBatteryDisconnected() bool {
+	battery := e.GetBatteryEvent()
+	return battery != nil && battery.Component == "BATTERY_COMPONENT_VEHICLE" &&
+		(battery.Status == "BATTERY_OFFLINE" || battery.Name == "BATTERY_DISCONNECTED")
+}
+
+func (e *Event) GetBatteryEvent() *BatteryEvent {
+	if e.Attributes.Data == nil {
+		return nil
+	}
+
+	payload := e.Attributes.Data.TripEvent
+	if payload == nil {
+		payload = e.Attributes.Data.StandaloneEvent
+	}
+	if payload == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil
+	}
+
+	var event struct {
+		Telemetry *struct {
+			EventName string        `json:"event_name"`
+			Battery   *BatteryEvent `json:"battery"`
+		} `json:"telemetry"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil || event.Telemetry == nil || event.Telemetry.EventName != "BATTERY_EVENT" {
+		return nil
+	}
+
+	return event.Telemetry.Battery
+}
@@ -160,3 +160,108 @@ func TestTelemetryEvent_GetTelemetryData_InvalidJSON(t *testing.T) {
 		t.Error("GetTelemetryData() retornou Telemetry, esperava nil para JSON inválido")
 	}
 }
+
+func TestTelemetryEvent_GetIgnitionStatus(t *testing.T) {
+	baseEvent := &base.BaseEvent{
+		ID:       "event-123",
+		Category: base.EventCategory("EVENT_CATEGORY_VEHICLE"),
+		Attributes: base.Attributes{Data: &base.Data{
+			TripEvent: map[string]interface{}{
+				"trip_id": "trip-001",
+				"telemetry": map[string]interface{}{
+					"id":         "telemetry-789",
+					"event_name": "IGNITION",
+					"ignition": map[string]interface{}{
+						"name":   "IGNITION",
+						"status": "IGNITION_STATUS_OFF",
+					},
+				},
+			},
+		}},
+	}
+
+	event := New(baseEvent)
+
+	if got := event.GetTripTelemetry(); got == nil || got.EventName != "IGNITION" {
+		t.Errorf("GetTripTelemetry() = %+v, esperava evento IGNITION", got)
+	}
+	if got := event.GetIgnitionStatus(); got != IgnitionStatusOff {
+		t.Errorf("GetIgnitionStatus() = %s, esperava %s", got, IgnitionStatusOff)
+	}
+	if got := New(&base.BaseEvent{}).GetIgnitionStatus(); got != "" {
+		t.Errorf("GetIgnitionStatus() = %s, esperava string vazia", got)
+	}
+}
+
+func TestTelemetryEvent_GetOdometer(t *testing.T) {
+	event := New(&base.BaseEvent{Attributes: base.Attributes{Data: &base.Data{
+		Telemetry: map[string]interface{}{
+			"metrics": map[string]interface{}{
+				"odometer": map[string]interface{}{"value": 125000},
+			},
+		},
+	}}})
+
+	if got, ok := event.GetOdometer(); !ok || got != 125000 {
+		t.Errorf("GetOdometer() = %f, %v, esperava 125000, true", got, ok)
+	}
+	if _, ok := New(&base.BaseEvent{}).GetOdometer(); ok {
+		t.Error("GetOdometer() = true, esperava false sem telemetria")
+	}
+}
+
+func TestTelemetryEvent_GetBatteryEvent(t *testing.T) {
+	event := New(&base.BaseEvent{Attributes: base.Attributes{Data: &base.Data{
+		StandaloneEvent: map[string]interface{}{
+			"event_group_name": "TELEMETRY",
+			"telemetry": map[string]interface{}{
+				"event_name": "BATTERY_EVENT",
+				"battery": map[string]interface{}{
+					"name":      "BATTERY_DISCONNECTED",
+					"status":    "BATTERY_OFFLINE",
+					"component": "BATTERY_COMPONENT_VEHICLE",
+				},
+			},
+		},
+	}}})
+
+	got := event.GetBatteryEvent()
+	if got == nil || got.Status != "BATTERY_OFFLINE" || got.Component != "BATTERY_COMPONENT_VEHICLE" {
+		t.Errorf("GetBatteryEvent() = %+v, esperava bateria do veículo offline", got)
+	}
+	if got := New(&base.BaseEvent{}).GetBatteryEvent(); got != nil {
+		t.Errorf("GetBatteryEvent() = %+v, esperava nil", got)
+	}
+	if !event.IsVehicleBatteryDisconnected() {
+		t.Error("IsVehicleBatteryDisconnected() = false, esperava true")
+	}
+	if New(&base.BaseEvent{}).IsVehicleBatteryDisconnected() {
+		t.Error("IsVehicleBatteryDisconnected() = true, esperava false sem evento de bateria")
+	}
+}
+
+func TestTelemetryEvent_GetCurrentIgnitionStatus(t *testing.T) {
+	periodic := New(&base.BaseEvent{Attributes: base.Attributes{Data: &base.Data{
+		Telemetry: map[string]interface{}{"status": "IGNITION_STATUS_ON"},
+	}}})
+	if got := periodic.GetCurrentIgnitionStatus(); got != IgnitionStatusOn {
+		t.Errorf("GetCurrentIgnitionStatus() = %s, esperava %s", got, IgnitionStatusOn)
+	}
+
+	trip := New(&base.BaseEvent{Attributes: base.Attributes{Data: &base.Data{
+		Telemetry: map[string]interface{}{"status": "IGNITION_STATUS_ON"},
+		TripEvent: map[string]interface{}{
+			"telemetry": map[string]interface{}{
+				"event_name": "IGNITION",
+				"ignition":   map[string]interface{}{"status": "IGNITION_STATUS_OFF"},
+			},
+		},
+	}}})
+	if got := trip.GetCurrentIgnitionStatus(); got != IgnitionStatusOff {
+		t.Errorf("GetCurrentIgnitionStatus() = %s, esperava %s do evento de ignição", got, IgnitionStatusOff)
+	}
+
+	if got := New(&base.BaseEvent{}).GetCurrentIgnitionStatus(); got != "" {
+		t.Errorf("GetCurrentIgnitionStatus() = %s, esperava string vazia", got)
+	}
+}
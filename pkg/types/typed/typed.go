@@ -0,0 +1,49 @@
+// Package typed wraps a base.BaseEvent in the event type of its category.
+// It only depends on the event type packages, so analyzers can use it
+// without pulling in storage drivers.
+package typed
+
+import (
+	"strings"
+
+	"go-eventlib/pkg/types/alert"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/connection"
+	"go-eventlib/pkg/types/dms"
+	"go-eventlib/pkg/types/driverbehavior"
+	"go-eventlib/pkg/types/hardware"
+	"go-eventlib/pkg/types/order"
+	"go-eventlib/pkg/types/system"
+	"go-eventlib/pkg/types/telemetry"
+	"go-eventlib/pkg/types/vehicle"
+	"go-eventlib/pkg/types/vision"
+)
+
+// Wrap returns the typed event for the category of event (*dms.Event,
+// *telemetry.Event, ...), or event itself for unknown categories.
+func Wrap(event *base.BaseEvent) base.Event {
+	switch strings.TrimPrefix(string(event.GetCategory()), "EVENT_CATEGORY_") {
+	case "ALERT":
+		return alert.New(event)
+	case "CONNECTION":
+		return connection.New(event)
+	case "DMS":
+		return dms.New(event)
+	case "DRIVER_BEHAVIOR":
+		return driverbehavior.New(event)
+	case "HEALTH":
+		return hardware.New(event)
+	case "ORDER":
+		return order.New(event)
+	case "SYSTEM":
+		return system.New(event)
+	case "TELEMETRY":
+		return telemetry.New(event)
+	case "VEHICLE":
+		return vehicle.New(event)
+	case "VISION":
+		return vision.New(event)
+	default:
+		return event
+	}
+}
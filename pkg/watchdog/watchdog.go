@@ -0,0 +1,232 @@
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/telemetry"
+)
+
+const (
+	EventDeviceSilent  = "DEVICE_SILENT"
+	EventDeviceResumed = "DEVICE_RESUMED"
+)
+
+type Kind string
+
+const (
+	DeviceSilent  Kind = EventDeviceSilent
+	DeviceResumed Kind = EventDeviceResumed
+)
+
+type Transition struct {
+	Kind      Kind
+	DeviceID  string
+	AccountID string
+	Ignition  telemetry.IgnitionStatus
+	LastSeen  time.Time
+	At        time.Time
+	Silence   time.Duration
+}
+
+func (t *Transition) Event() *base.BaseEvent {
+	name := string(t.Kind)
+	detail := map[string]interface{}{
+		"name":            name,
+		"last_seen":       t.LastSeen.Format(time.RFC3339Nano),
+		"silence_seconds": t.Silence.Seconds(),
+		"ignition":        string(t.Ignition),
+	}
+
+	return &base.BaseEvent{
+		ID:        fmt.Sprintf("watchdog-%s-%s-%d", t.DeviceID, name, t.At.UnixNano()),
+		Status:    "STATUS_RECEIVED",
+		CreatedAt: t.At,
+		Type:      "EVENT_TYPE_DERIVED",
+		Category:  "EVENT_CATEGORY_SYSTEM",
+		Attributes: base.Attributes{
+			Device: &base.Device{ID: t.DeviceID, AccountID: t.AccountID},
+			Data: &base.Data{
+				GroupName: "STANDALONE_EVENT",
+				StandaloneEvent: map[string]interface{}{
+					"event_group_name": "SYSTEM",
+					"system": map[string]interface{}{
+						"event_name": name,
+						"timestamp":  t.At.Format(time.RFC3339Nano),
+						"watchdog":   detail,
+					},
+				},
+			},
+		},
+	}
+}
+
+type Config struct {
+	Threshold           time.Duration
+	IgnitionOnThreshold time.Duration
+	Interval            time.Duration
+	Now                 func() time.Time
+	Handler             dispatch.Handler
+	OnError             func(error)
+}
+
+type device struct {
+	accountID string
+	ignition  telemetry.IgnitionStatus
+	lastSeen  time.Time
+	silent    bool
+}
+
+type Watchdog struct {
+	cfg Config
+
+	mu      sync.Mutex
+	devices map[string]*device
+}
+
+func NewWatchdog(cfg Config) *Watchdog {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = 30 * time.Minute
+	}
+	if cfg.IgnitionOnThreshold <= 0 {
+		cfg.IgnitionOnThreshold = 5 * time.Minute
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+
+	return &Watchdog{
+		cfg:     cfg,
+		devices: make(map[string]*device),
+	}
+}
+
+func (w *Watchdog) Observe(event *base.BaseEvent) *Transition {
+	deviceID := event.GetDeviceID()
+	if deviceID == "" || event.Type == "EVENT_TYPE_DERIVED" {
+		return nil
+	}
+
+	now := w.cfg.Now()
+	ignition := telemetry.New(event).GetCurrentIgnitionStatus()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	d, ok := w.devices[deviceID]
+	if !ok {
+		d = &device{}
+		w.devices[deviceID] = d
+	}
+	if accountID := event.GetAccountID(); accountID != "" {
+		d.accountID = accountID
+	}
+	if ignition != "" {
+		d.ignition = ignition
+	}
+
+	var resumed *Transition
+	if d.silent {
+		d.silent = false
+		resumed = &Transition{
+			Kind:      DeviceResumed,
+			DeviceID:  deviceID,
+			AccountID: d.accountID,
+			Ignition:  d.ignition,
+			LastSeen:  d.lastSeen,
+			At:        now,
+			Silence:   now.Sub(d.lastSeen),
+		}
+	}
+	d.lastSeen = now
+	return resumed
+}
+
+func (w *Watchdog) Check() []*Transition {
+	now := w.cfg.Now()
+
+	w.mu.Lock()
+	var transitions []*Transition
+	for deviceID, d := range w.devices {
+		silence := now.Sub(d.lastSeen)
+		if d.silent || silence < w.threshold(d.ignition) {
+			continue
+		}
+		d.silent = true
+		transitions = append(transitions, &Transition{
+			Kind:      DeviceSilent,
+			DeviceID:  deviceID,
+			AccountID: d.accountID,
+			Ignition:  d.ignition,
+			LastSeen:  d.lastSeen,
+			At:        now,
+			Silence:   silence,
+		})
+	}
+	w.mu.Unlock()
+
+	sort.Slice(transitions, func(i, j int) bool { return transitions[i].DeviceID < transitions[j].DeviceID })
+	return transitions
+}
+
+func (w *Watchdog) Silent(deviceID string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	d, ok := w.devices[deviceID]
+	return ok && d.silent
+}
+
+func (w *Watchdog) Forget(deviceID string) {
+	w.mu.Lock()
+	delete(w.devices, deviceID)
+	w.mu.Unlock()
+}
+
+func (w *Watchdog) Handler() dispatch.Handler {
+	return func(ctx context.Context, event *base.BaseEvent) error {
+		t := w.Observe(event)
+		if t == nil || w.cfg.Handler == nil {
+			return nil
+		}
+		return w.cfg.Handler(ctx, t.Event())
+	}
+}
+
+// Run calls Check every Interval and sends DEVICE_SILENT events to
+// Config.Handler until ctx is done. Handler errors go to Config.OnError.
+func (w *Watchdog) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			for _, t := range w.Check() {
+				if w.cfg.Handler == nil {
+					continue
+				}
+				if err := w.cfg.Handler(ctx, t.Event()); err != nil && w.cfg.OnError != nil {
+					w.cfg.OnError(err)
+				}
+			}
+		}
+	}
+}
+
+func (w *Watchdog) threshold(ignition telemetry.IgnitionStatus) time.Duration {
+	if ignition == telemetry.IgnitionStatusOn {
+		return w.cfg.IgnitionOnThreshold
+	}
+	return w.cfg.Threshold
+}
@@ -0,0 +1,118 @@
+package watchdog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-eventlib/pkg/types/base"
+)
+
+type clock struct{ now time.Time }
+
+func newClock() *clock {
+	return &clock{now: time.Date(2025, 12, 15, 19, 0, 0, 0, time.UTC)}
+}
+
+func (c *clock) Now() time.Time          { return c.now }
+func (c *clock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func event(device string, ignition string) *base.BaseEvent {
+	event := &base.BaseEvent{
+		ID:       device + "-event",
+		Category: "EVENT_CATEGORY_SYSTEM",
+		Attributes: base.Attributes{
+			Device: &base.Device{ID: device, AccountID: "account-1"},
+			Data:   &base.Data{},
+		},
+	}
+	if ignition != "" {
+		event.Attributes.Data.Telemetry = map[string]interface{}{"status": ignition}
+	}
+	return event
+}
+
+func TestWatchdog_FiresSilentAfterThreshold(t *testing.T) {
+	c := newClock()
+	w := NewWatchdog(Config{Threshold: 30 * time.Minute, IgnitionOnThreshold: 5 * time.Minute, Now: c.Now})
+
+	w.Observe(event("device-on", "IGNITION_STATUS_ON"))
+	w.Observe(event("device-off", "IGNITION_STATUS_OFF"))
+
+	c.Advance(4 * time.Minute)
+	if got := w.Check(); len(got) != 0 {
+		t.Fatalf("Check() antes do limite = %+v", got)
+	}
+
+	c.Advance(2 * time.Minute)
+	got := w.Check()
+	if len(got) != 1 || got[0].DeviceID != "device-on" || got[0].Kind != DeviceSilent {
+		t.Fatalf("Check() = %+v, esperava device-on silencioso (ignição ligada)", got)
+	}
+	if got[0].Silence != 6*time.Minute || got[0].AccountID != "account-1" {
+		t.Errorf("Transition = %+v", got[0])
+	}
+	if got := w.Check(); len(got) != 0 {
+		t.Errorf("Check() repetiu DEVICE_SILENT: %+v", got)
+	}
+
+	c.Advance(25 * time.Minute)
+	got = w.Check()
+	if len(got) != 1 || got[0].DeviceID != "device-off" {
+		t.Fatalf("Check() = %+v, esperava device-off silencioso", got)
+	}
+	if !w.Silent("device-off") || !w.Silent("device-on") {
+		t.Error("Silent() = false para dispositivos silenciosos")
+	}
+}
+
+func TestWatchdog_ResumedWhenTrafficReturns(t *testing.T) {
+	c := newClock()
+	w := NewWatchdog(Config{Threshold: time.Minute, Now: c.Now})
+
+	w.Observe(event("device-1", ""))
+	c.Advance(2 * time.Minute)
+	w.Check()
+
+	c.Advance(3 * time.Minute)
+	resumed := w.Observe(event("device-1", ""))
+	if resumed == nil || resumed.Kind != DeviceResumed || resumed.Silence != 5*time.Minute {
+		t.Fatalf("Observe() = %+v, esperava DEVICE_RESUMED após 5m", resumed)
+	}
+	if w.Silent("device-1") {
+		t.Error("Silent() = true após retomada")
+	}
+	if again := w.Observe(event("device-1", "")); again != nil {
+		t.Errorf("Observe() repetiu DEVICE_RESUMED: %+v", again)
+	}
+}
+
+func TestWatchdog_HandlerEmitsDerivedEvents(t *testing.T) {
+	c := newClock()
+	var emitted []*base.BaseEvent
+	w := NewWatchdog(Config{
+		Threshold: time.Minute,
+		Now:       c.Now,
+		Handler: func(ctx context.Context, event *base.BaseEvent) error {
+			emitted = append(emitted, event)
+			return nil
+		},
+	})
+	handler := w.Handler()
+
+	handler(context.Background(), event("device-1", ""))
+	c.Advance(2 * time.Minute)
+	for _, tr := range w.Check() {
+		handler(context.Background(), tr.Event())
+	}
+	handler(context.Background(), event("device-1", ""))
+
+	if len(emitted) != 1 || emitted[0].GetEventName() != EventDeviceResumed {
+		t.Fatalf("emitted = %+v, esperava um DEVICE_RESUMED", emitted)
+	}
+
+	silent := (&Transition{Kind: DeviceSilent, DeviceID: "device-1", At: c.Now()}).Event()
+	if silent.GetEventName() != EventDeviceSilent || silent.GetDeviceID() != "device-1" || silent.Type != "EVENT_TYPE_DERIVED" {
+		t.Errorf("Event() = %+v", silent)
+	}
+}
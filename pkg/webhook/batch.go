@@ -0,0 +1,161 @@
+package webhook
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const maxLineSize = 16 << 20
+
+type ItemStatus string
+
+const (
+	ItemStatusOK              ItemStatus = "ok"
+	ItemStatusParseError      ItemStatus = "parse_error"
+	ItemStatusValidationError ItemStatus = "validation_error"
+	ItemStatusHandlerError    ItemStatus = "handler_error"
+)
+
+type ItemResult struct {
+	Index  int
+	ID     string
+	Status ItemStatus
+	Err    error
+}
+
+type BatchResult struct {
+	Items []ItemResult
+}
+
+func (b *BatchResult) Succeeded() int {
+	n := 0
+	for _, item := range b.Items {
+		if item.Status == ItemStatusOK {
+			n++
+		}
+	}
+	return n
+}
+
+func (b *BatchResult) Failed() []ItemResult {
+	var failed []ItemResult
+	for _, item := range b.Items {
+		if item.Status != ItemStatusOK {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+func ProcessBatch(ctx context.Context, r io.Reader, processor Processor) (*BatchResult, error) {
+	br := bufio.NewReader(r)
+
+	first, err := peekNonSpace(br)
+	if err == io.EOF {
+		return &BatchResult{}, nil
+	}
+	if err != nil {
+		return &BatchResult{}, fmt.Errorf("%w: %v", ErrParse, err)
+	}
+
+	if first == '[' {
+		return processArray(ctx, br, processor)
+	}
+	return processLines(ctx, br, processor)
+}
+
+func processArray(ctx context.Context, r io.Reader, processor Processor) (*BatchResult, error) {
+	result := &BatchResult{}
+	dec := json.NewDecoder(r)
+
+	if _, err := dec.Token(); err != nil {
+		return result, fmt.Errorf("%w: %v", ErrParse, err)
+	}
+
+	for index := 0; dec.More(); index++ {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return result, fmt.Errorf("%w: element %d: %v", ErrParse, index, err)
+		}
+
+		result.Items = append(result.Items, processItem(ctx, processor, index, raw))
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return result, fmt.Errorf("%w: %v", ErrParse, err)
+	}
+
+	return result, nil
+}
+
+func processLines(ctx context.Context, r io.Reader, processor Processor) (*BatchResult, error) {
+	result := &BatchResult{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	index := 0
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		result.Items = append(result.Items, processItem(ctx, processor, index, line))
+		index++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("%w: line %d: %v", ErrParse, index, err)
+	}
+
+	return result, nil
+}
+
+func processItem(ctx context.Context, processor Processor, index int, data []byte) ItemResult {
+	event, err := processor.ProcessEvent(ctx, data)
+
+	item := ItemResult{Index: index, Status: itemStatus(err), Err: err}
+	if event != nil {
+		item.ID = event.GetID()
+	}
+	return item
+}
+
+func itemStatus(err error) ItemStatus {
+	switch {
+	case err == nil:
+		return ItemStatusOK
+	case errors.Is(err, ErrParse):
+		return ItemStatusParseError
+	case errors.Is(err, ErrValidation):
+		return ItemStatusValidationError
+	default:
+		return ItemStatusHandlerError
+	}
+}
+
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b, br.UnreadByte()
+	}
+}
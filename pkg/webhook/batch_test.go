@@ -0,0 +1,205 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-eventlib/pkg/types/base"
+)
+
+func eventJSON(id string) string {
+	return fmt.Sprintf(`{"id":%q,"category":"EVENT_CATEGORY_DMS","created_at":"2025-12-15T19:02:27Z"}`, id)
+}
+
+func failingProcessor(failID string) Processor {
+	return NewHandlerProcessor(func(ctx context.Context, event *base.BaseEvent) error {
+		if event.ID == failID {
+			return errors.New("falha no handler")
+		}
+		return nil
+	})
+}
+
+func TestProcessBatch_ArrayPerItemResults(t *testing.T) {
+	body := "[" + strings.Join([]string{
+		eventJSON("event-0"),
+		`{"id":5}`,
+		`{"id":"event-2"}`,
+		eventJSON("event-3"),
+	}, ",") + "]"
+
+	result, err := ProcessBatch(context.Background(), strings.NewReader(body), failingProcessor("event-3"))
+	if err != nil {
+		t.Fatalf("ProcessBatch() erro inesperado: %v", err)
+	}
+
+	want := []ItemStatus{ItemStatusOK, ItemStatusParseError, ItemStatusValidationError, ItemStatusHandlerError}
+	if len(result.Items) != len(want) {
+		t.Fatalf("itens = %d, esperava %d", len(result.Items), len(want))
+	}
+	for i, status := range want {
+		if result.Items[i].Index != i || result.Items[i].Status != status {
+			t.Errorf("item %d = %+v, esperava status %s", i, result.Items[i], status)
+		}
+	}
+	if result.Succeeded() != 1 || len(result.Failed()) != 3 {
+		t.Errorf("Succeeded() = %d, Failed() = %d, esperava 1 e 3", result.Succeeded(), len(result.Failed()))
+	}
+	if result.Items[3].ID != "event-3" {
+		t.Errorf("Items[3].ID = %s, esperava event-3", result.Items[3].ID)
+	}
+}
+
+func TestProcessBatch_NDJSON(t *testing.T) {
+	body := eventJSON("event-0") + "\n" + `{"id":` + "\n\n" + eventJSON("event-2") + "\n"
+
+	result, err := ProcessBatch(context.Background(), strings.NewReader(body), okProcessor())
+	if err != nil {
+		t.Fatalf("ProcessBatch() erro inesperado: %v", err)
+	}
+
+	want := []ItemStatus{ItemStatusOK, ItemStatusParseError, ItemStatusOK}
+	if len(result.Items) != len(want) {
+		t.Fatalf("itens = %d, esperava %d", len(result.Items), len(want))
+	}
+	for i, status := range want {
+		if result.Items[i].Status != status {
+			t.Errorf("item %d = %s, esperava %s", i, result.Items[i].Status, status)
+		}
+	}
+	if result.Items[2].ID != "event-2" {
+		t.Errorf("Items[2].ID = %s, esperava event-2", result.Items[2].ID)
+	}
+}
+
+func TestProcessBatch_TruncatedArray(t *testing.T) {
+	body := "[" + eventJSON("event-0") + `,{"id":"event-1"`
+
+	result, err := ProcessBatch(context.Background(), strings.NewReader(body), okProcessor())
+	if !errors.Is(err, ErrParse) {
+		t.Errorf("ProcessBatch() = %v, esperava ErrParse", err)
+	}
+	if len(result.Items) != 1 || result.Items[0].Status != ItemStatusOK {
+		t.Errorf("itens = %+v, esperava o primeiro evento processado", result.Items)
+	}
+}
+
+func TestProcessBatch_Streams(t *testing.T) {
+	pr, pw := io.Pipe()
+	seen := make(chan string, 1)
+
+	processor := NewHandlerProcessor(func(ctx context.Context, event *base.BaseEvent) error {
+		seen <- event.ID
+		return nil
+	})
+
+	done := make(chan *BatchResult)
+	go func() {
+		result, _ := ProcessBatch(context.Background(), pr, processor)
+		done <- result
+	}()
+
+	io.WriteString(pw, "["+eventJSON("event-0")+",")
+
+	select {
+	case id := <-seen:
+		if id != "event-0" {
+			t.Errorf("primeiro evento = %s, esperava event-0", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("o primeiro evento não foi processado antes do fim do corpo")
+	}
+
+	io.WriteString(pw, eventJSON("event-1")+"]")
+	pw.Close()
+	<-seen
+
+	if result := <-done; len(result.Items) != 2 {
+		t.Errorf("itens = %d, esperava 2", len(result.Items))
+	}
+}
+
+func TestHTTPHandler_NDJSON(t *testing.T) {
+	body := eventJSON("event-0") + "\n" + eventJSON("event-1") + "\n"
+	req := post([]byte(body))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	rec, resp := serve(NewHTTPHandler(okProcessor()), req)
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("status = %d, esperava 202", rec.Code)
+	}
+	if len(resp.Results) != 2 {
+		t.Errorf("resultados = %+v, esperava 2", resp.Results)
+	}
+}
+
+func TestHTTPHandler_StreamsBody(t *testing.T) {
+	pr, pw := io.Pipe()
+	seen := make(chan string, 2)
+
+	handler := NewHTTPHandler(NewHandlerProcessor(func(ctx context.Context, event *base.BaseEvent) error {
+		seen <- event.ID
+		return nil
+	}))
+
+	done := make(chan int)
+	go func() {
+		rec, _ := serve(handler, httptest.NewRequest(http.MethodPost, "/webhook", pr))
+		done <- rec.Code
+	}()
+
+	io.WriteString(pw, "["+eventJSON("event-0")+",")
+	select {
+	case <-seen:
+	case <-time.After(time.Second):
+		t.Fatal("o primeiro evento não foi processado antes do fim do corpo")
+	}
+
+	io.WriteString(pw, eventJSON("event-1")+"]")
+	pw.Close()
+	if code := <-done; code != http.StatusAccepted {
+		t.Errorf("status = %d, esperava 202", code)
+	}
+}
+
+func TestHTTPHandler_BatchErrorKeepsResults(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		opts []HTTPOption
+		want []int
+	}{
+		{"array truncado", "[" + eventJSON("event-0") + "," + eventJSON("event-1") + `,{"id":`, nil,
+			[]int{http.StatusAccepted, http.StatusAccepted, http.StatusBadRequest}},
+		{"corpo grande", "[" + eventJSON("event-0") + "," + eventJSON("event-1") + "]",
+			[]HTTPOption{WithMaxBodySize(int64(len(eventJSON("event-0")) + 8))},
+			[]int{http.StatusAccepted, http.StatusRequestEntityTooLarge}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, resp := serve(NewHTTPHandler(okProcessor(), tt.opts...), post([]byte(tt.body)))
+			if rec.Code != http.StatusMultiStatus {
+				t.Errorf("status = %d, esperava 207", rec.Code)
+			}
+
+			var got []int
+			for i, result := range resp.Results {
+				if result.Index != i {
+					t.Errorf("resultado %d com índice %d", i, result.Index)
+				}
+				got = append(got, result.Status)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("status = %v, esperava %v", got, tt.want)
+			}
+		})
+	}
+}
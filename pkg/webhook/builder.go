@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/types/base"
+)
+
+// EventProcessor is the Processor built by EventProcessorBuilder. It also
+// accepts a JSON array or NDJSON batch in a single call.
+type EventProcessor interface {
+	Processor
+	ProcessEvents(ctx context.Context, data []byte) ([]*base.BaseEvent, error)
+}
+
+var _ EventProcessor = (*HandlerProcessor)(nil)
+
+type EventProcessorBuilder struct {
+	routes      []builderRoute
+	middlewares []dispatch.Middleware
+}
+
+type builderRoute struct {
+	handler dispatch.Handler
+	filter  dispatch.Filter
+}
+
+func NewEventProcessorBuilder() *EventProcessorBuilder {
+	return &EventProcessorBuilder{}
+}
+
+// WithHandler registers a plain handler. Without filters it sees every
+// event; otherwise only events matching at least one of filters.
+func (b *EventProcessorBuilder) WithHandler(handler dispatch.Handler, filters ...dispatch.Filter) *EventProcessorBuilder {
+	b.routes = append(b.routes, builderRoute{handler: handler, filter: dispatch.AnyOf(filters...)})
+	return b
+}
+
+// WithMiddleware wraps every registered handler, in the order given. Each
+// handler is wrapped on its own, so a retry or timeout of one handler does
+// not re-run the others.
+func (b *EventProcessorBuilder) WithMiddleware(middlewares ...dispatch.Middleware) *EventProcessorBuilder {
+	b.middlewares = append(b.middlewares, middlewares...)
+	return b
+}
+
+func (b *EventProcessorBuilder) WithCategoryMiddleware(category base.EventCategory, middlewares ...dispatch.Middleware) *EventProcessorBuilder {
+	return b.WithMiddleware(dispatch.ForCategory(category, middlewares...))
+}
+
+func (b *EventProcessorBuilder) WithEventHandler(h *EventHandler, filters ...dispatch.Filter) *EventProcessorBuilder {
+	return b.WithHandler(h.Handle, filters...)
+}
+
+func (b *EventProcessorBuilder) WithConnectionHandler(h *ConnectionHandler, filters ...dispatch.Filter) *EventProcessorBuilder {
+	return b.WithHandler(h.Handle, filters...)
+}
+
+func (b *EventProcessorBuilder) WithVisionHandler(h *VisionHandler, filters ...dispatch.Filter) *EventProcessorBuilder {
+	return b.WithHandler(h.Handle, filters...)
+}
+
+func (b *EventProcessorBuilder) WithHardwareHandler(h *HardwareHandler, filters ...dispatch.Filter) *EventProcessorBuilder {
+	return b.WithHandler(h.Handle, filters...)
+}
+
+func (b *EventProcessorBuilder) WithSystemHandler(h *SystemHandler, filters ...dispatch.Filter) *EventProcessorBuilder {
+	return b.WithHandler(h.Handle, filters...)
+}
+
+func (b *EventProcessorBuilder) WithTelemetryHandler(h *TelemetryHandler, filters ...dispatch.Filter) *EventProcessorBuilder {
+	return b.WithHandler(h.Handle, filters...)
+}
+
+func (b *EventProcessorBuilder) WithAlertHandler(h *AlertHandler, filters ...dispatch.Filter) *EventProcessorBuilder {
+	return b.WithHandler(h.Handle, filters...)
+}
+
+func (b *EventProcessorBuilder) WithDMSHandler(h *DMSHandler, filters ...dispatch.Filter) *EventProcessorBuilder {
+	return b.WithHandler(h.Handle, filters...)
+}
+
+func (b *EventProcessorBuilder) WithDriverBehaviorHandler(h *DriverBehaviorHandler, filters ...dispatch.Filter) *EventProcessorBuilder {
+	return b.WithHandler(h.Handle, filters...)
+}
+
+func (b *EventProcessorBuilder) WithVehicleHandler(h *VehicleHandler, filters ...dispatch.Filter) *EventProcessorBuilder {
+	return b.WithHandler(h.Handle, filters...)
+}
+
+// Build returns a HandlerProcessor running the registered handlers in
+// registration order; their errors are joined.
+func (b *EventProcessorBuilder) Build() *HandlerProcessor {
+	router := dispatch.NewRouter()
+	for _, route := range b.routes {
+		router.Handle(route.filter, route.handler, b.middlewares...)
+	}
+	return NewHandlerProcessor(router.Handler())
+}
+
+// ProcessEvents processes a JSON array or NDJSON body event by event and
+// returns the events that were handled successfully. Failures of single
+// events are joined into the error; the remaining events still run.
+func (p *HandlerProcessor) ProcessEvents(ctx context.Context, data []byte) ([]*base.BaseEvent, error) {
+	var events []*base.BaseEvent
+	collect := processorFunc(func(ctx context.Context, data []byte) (*base.BaseEvent, error) {
+		event, err := p.ProcessEvent(ctx, data)
+		if err == nil {
+			events = append(events, event)
+		}
+		return event, err
+	})
+
+	result, err := ProcessBatch(ctx, bytes.NewReader(data), collect)
+	errs := []error{err}
+	for _, item := range result.Failed() {
+		errs = append(errs, fmt.Errorf("event %d: %w", item.Index, item.Err))
+	}
+	return events, errors.Join(errs...)
+}
+
+type processorFunc func(ctx context.Context, data []byte) (*base.BaseEvent, error)
+
+func (f processorFunc) ProcessEvent(ctx context.Context, data []byte) (*base.BaseEvent, error) {
+	return f(ctx, data)
+}
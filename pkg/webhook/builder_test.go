@@ -0,0 +1,198 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/types/alert"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/connection"
+	"go-eventlib/pkg/types/dms"
+	"go-eventlib/pkg/types/driverbehavior"
+	"go-eventlib/pkg/types/hardware"
+	"go-eventlib/pkg/types/order"
+	"go-eventlib/pkg/types/system"
+	"go-eventlib/pkg/types/telemetry"
+	"go-eventlib/pkg/types/vehicle"
+	"go-eventlib/pkg/types/vision"
+)
+
+func record[E any](calls *[]string, name string) func(ctx context.Context, event E) error {
+	return func(ctx context.Context, event E) error {
+		*calls = append(*calls, name)
+		return nil
+	}
+}
+
+func recordingProcessor(calls *[]string) *HandlerProcessor {
+	return NewEventProcessorBuilder().
+		WithEventHandler(&EventHandler{
+			OnOrderReceived: record[*order.Event](calls, "OrderReceived"),
+			OnOrderAck:      record[*order.Event](calls, "OrderAck"),
+		}).
+		WithConnectionHandler(&ConnectionHandler{
+			OnWifiConnected:    record[*connection.Event](calls, "WifiConnected"),
+			OnWifiDisconnected: record[*connection.Event](calls, "WifiDisconnected"),
+			OnSimCardChanged:   record[*connection.Event](calls, "SimCardChanged"),
+		}).
+		WithVisionHandler(&VisionHandler{
+			OnFaceLost:         record[*vision.Event](calls, "FaceLost"),
+			OnCameraObstructed: record[*vision.Event](calls, "CameraObstructed"),
+			OnVisionAlert:      record[*vision.Event](calls, "VisionAlert"),
+		}).
+		WithHardwareHandler(&HardwareHandler{
+			OnDeviceRestart:              record[*hardware.Event](calls, "DeviceRestart"),
+			OnVehicleBatteryDisconnected: record[*hardware.Event](calls, "VehicleBatteryDisconnected"),
+			OnSDCardUnmounted:            record[*hardware.Event](calls, "SDCardUnmounted"),
+			OnSimCardRemoved:             record[*hardware.Event](calls, "SimCardRemoved"),
+		}).
+		WithSystemHandler(&SystemHandler{OnUploadEvent: record[*system.Event](calls, "Upload")}).
+		WithTelemetryHandler(&TelemetryHandler{OnIgnitionEvent: record[*telemetry.Event](calls, "Ignition")}).
+		WithAlertHandler(&AlertHandler{OnCriticalAlert: record[*alert.Event](calls, "CriticalAlert")}).
+		WithDMSHandler(&DMSHandler{
+			OnDrowsiness:      record[*dms.Event](calls, "Drowsiness"),
+			OnPoseDistraction: record[*dms.Event](calls, "PoseDistraction"),
+			OnDMSAlert:        record[*dms.Event](calls, "DMSAlert"),
+		}).
+		WithDriverBehaviorHandler(&DriverBehaviorHandler{
+			OnMaxSpeedFault: record[*driverbehavior.Event](calls, "MaxSpeedFault"),
+			OnSharpTurn:     record[*driverbehavior.Event](calls, "SharpTurn"),
+		}).
+		WithVehicleHandler(&VehicleHandler{OnIgnitionOff: record[*vehicle.Event](calls, "IgnitionOff")}).
+		Build()
+}
+
+func TestEventProcessorBuilder_TypedCallbacks(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    string
+	}{
+		{"ack-events/ack-order-event.json", "OrderAck"},
+		{"ack-events/ack-upload-event.json", "Upload"},
+		{"hardware-events/hardware-wifi-connected.json", "WifiConnected"},
+		{"hardware-events/hardware-simcard-removed.json", "SimCardChanged,SimCardRemoved"},
+		{"vision-basic-events/vision-face-lost.json", "FaceLost,VisionAlert"},
+		{"vision-basic-events/vision-camera-obstructed.json", "CameraObstructed,VisionAlert"},
+		{"hardware-events/hardware-reboot.json", "DeviceRestart"},
+		{"hardware-events/hardware-vehicle-battery-disconnected.json", "VehicleBatteryDisconnected"},
+		{"hardware-events/hardware-sdcard-unmounted.json", "SDCardUnmounted,CriticalAlert"},
+		{"telemetry-events/telemetry-ignition.json", "Ignition"},
+		{"telemetry-events/vehicle-ignition-off.json", "Ignition,IgnitionOff"},
+		{"dms-events/vision-drowsiness.json", "Drowsiness,DMSAlert"},
+		{"dms-events/vision-pose-distraction-yaw.json", "PoseDistraction,DMSAlert"},
+		{"driver-behavior-events/telemetry-max-speed-fault.json", "MaxSpeedFault"},
+		{"driver-behavior-events/telemetry-sharp-turn.json", "SharpTurn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			var calls []string
+			if _, err := recordingProcessor(&calls).ProcessEvent(context.Background(), readFixture(t, tt.fixture)); err != nil {
+				t.Fatalf("ProcessEvent() erro inesperado: %v", err)
+			}
+			if got := strings.Join(calls, ","); got != tt.want {
+				t.Errorf("callbacks = %s, esperava %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventProcessorBuilder_JoinsHandlerErrors(t *testing.T) {
+	errA := errors.New("falha a")
+	calls := 0
+	processor := NewEventProcessorBuilder().
+		WithDMSHandler(&DMSHandler{OnDrowsiness: func(ctx context.Context, event *dms.Event) error { return errA }}).
+		WithDMSHandler(&DMSHandler{OnDMSAlert: func(ctx context.Context, event *dms.Event) error {
+			calls++
+			return nil
+		}}).
+		Build()
+
+	if _, err := processor.ProcessEvent(context.Background(), readFixture(t, "dms-events/vision-drowsiness.json")); !errors.Is(err, errA) {
+		t.Errorf("ProcessEvent() erro = %v, esperava %v", err, errA)
+	}
+	if calls != 1 {
+		t.Errorf("segundo handler chamado %d vezes, esperava 1", calls)
+	}
+}
+
+func TestEventProcessorBuilder_FilteredHandlers(t *testing.T) {
+	var calls []string
+	processor := NewEventProcessorBuilder().
+		WithDMSHandler(&DMSHandler{OnDrowsiness: record[*dms.Event](&calls, "FleetA")},
+			dispatch.Filter{AccountIDs: []string{"01GZXXCVVPEKM7E830XAMJKA14"}}).
+		WithDMSHandler(&DMSHandler{OnDrowsiness: record[*dms.Event](&calls, "FleetB")},
+			dispatch.Filter{AccountIDs: []string{"account-b"}}).
+		WithDMSHandler(&DMSHandler{OnDrowsiness: record[*dms.Event](&calls, "Pilot")},
+			dispatch.Filter{DeviceIDs: []string{"pilot-1"}}, dispatch.Filter{EventNames: []string{"DROWSINESS"}}).
+		WithHandler(func(ctx context.Context, event *base.BaseEvent) error {
+			calls = append(calls, "All")
+			return nil
+		}).
+		Build()
+
+	if _, err := processor.ProcessEvent(context.Background(), readFixture(t, "dms-events/vision-drowsiness.json")); err != nil {
+		t.Fatalf("ProcessEvent() erro inesperado: %v", err)
+	}
+	if got := strings.Join(calls, ","); got != "FleetA,Pilot,All" {
+		t.Errorf("calls = %s, esperava FleetA,Pilot,All", got)
+	}
+}
+
+func TestHandlerProcessor_ProcessEvents(t *testing.T) {
+	var calls []string
+	var processor EventProcessor = recordingProcessor(&calls)
+
+	body := "[" + string(readFixture(t, "dms-events/vision-drowsiness.json")) + `,{"id":"x"},` +
+		string(readFixture(t, "vision-basic-events/vision-face-lost.json")) + "]"
+
+	events, err := processor.ProcessEvents(context.Background(), []byte(body))
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("ProcessEvents() erro = %v, esperava ErrValidation do evento 1", err)
+	}
+	if len(events) != 2 || events[1].GetEventName() != "FACE_LOST" {
+		t.Errorf("eventos = %d, esperava os 2 eventos válidos", len(events))
+	}
+	if got := strings.Join(calls, ","); got != "Drowsiness,DMSAlert,FaceLost,VisionAlert" {
+		t.Errorf("callbacks = %s", got)
+	}
+}
+
+func TestEventProcessorBuilder_WithMiddleware(t *testing.T) {
+	var trace []string
+	tag := func(name string) dispatch.Middleware {
+		return func(next dispatch.Handler) dispatch.Handler {
+			return func(ctx context.Context, event *base.BaseEvent) error {
+				trace = append(trace, name)
+				return next(ctx, event)
+			}
+		}
+	}
+
+	processor := NewEventProcessorBuilder().
+		WithDMSHandler(&DMSHandler{OnDrowsiness: func(ctx context.Context, event *dms.Event) error {
+			panic("falha no callback")
+		}}).
+		WithMiddleware(tag("global"), dispatch.Recover()).
+		WithCategoryMiddleware("EVENT_CATEGORY_VISION", tag("vision")).
+		WithVisionHandler(&VisionHandler{}).
+		Build()
+
+	_, err := processor.ProcessEvent(context.Background(), readFixture(t, "dms-events/vision-drowsiness.json"))
+	var panicErr *dispatch.PanicError
+	if !errors.As(err, &panicErr) {
+		t.Errorf("ProcessEvent() erro = %v, esperava *dispatch.PanicError", err)
+	}
+	if got := strings.Join(trace, ","); got != "global,global" {
+		t.Errorf("middlewares = %s, esperava global em cada handler", got)
+	}
+
+	trace = nil
+	processor.ProcessEvent(context.Background(), readFixture(t, "vision-basic-events/vision-face-lost.json"))
+	if got := strings.Join(trace, ","); got != "global,vision,global,vision" {
+		t.Errorf("middlewares = %s, esperava global e vision em cada handler", got)
+	}
+}
@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+
+	"google.golang.org/protobuf/proto"
+
+	"go-eventlib/pkg/codec"
+	"go-eventlib/pkg/types/base"
+)
+
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+var errDecodedTooLarge = fmt.Errorf("%w: decompressed payload over limit", ErrTooLarge)
+
+type Decoder func(data []byte) (*base.BaseEvent, error)
+
+func Decode(data []byte) (*base.BaseEvent, error) {
+	var event base.BaseEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrParse, err)
+	}
+	return &event, nil
+}
+
+// ProtobufDecoder decodes binary payloads of the message returned by
+// newMessage, normally the protocol-cloud event message, into a BaseEvent.
+func ProtobufDecoder(newMessage func() proto.Message) Decoder {
+	c := codec.New(newMessage)
+
+	return func(data []byte) (*base.BaseEvent, error) {
+		event, err := c.Decode(data, codec.FormatProtobuf)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrParse, err)
+		}
+		return event, nil
+	}
+}
+
+func mediaType(contentType string) string {
+	if contentType == "" {
+		return ContentTypeJSON
+	}
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return mt
+}
+
+func isGzip(data []byte) bool {
+	return len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b
+}
+
+func gunzip(data []byte, limit int64) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrParse, err)
+	}
+	defer zr.Close()
+
+	decoded, err := io.ReadAll(io.LimitReader(zr, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrParse, err)
+	}
+	if int64(len(decoded)) > limit {
+		return nil, errDecodedTooLarge
+	}
+	return decoded, nil
+}
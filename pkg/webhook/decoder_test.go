@@ -0,0 +1,172 @@
+package webhook
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"go-eventlib/internal/testpb"
+	"go-eventlib/pkg/types/base"
+)
+
+// toProtobuf encodes a JSON fixture as the typed testpb.Event message, so
+// timestamps and nested messages travel as typed protobuf fields.
+func toProtobuf(t *testing.T, jsonData []byte) []byte {
+	t.Helper()
+
+	msg := testpb.NewEvent()
+	if err := protojson.Unmarshal(jsonData, msg); err != nil {
+		t.Fatalf("protojson.Unmarshal() erro inesperado: %v", err)
+	}
+
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatalf("proto.Marshal() erro inesperado: %v", err)
+	}
+	return data
+}
+
+// sameEvent compares events by their JSON form: protobuf has no way to tell
+// an empty repeated field from a missing one.
+func sameEvent(a, b *base.BaseEvent) bool {
+	aJSON, _ := json.Marshal(a)
+	bJSON, _ := json.Marshal(b)
+	return bytes.Equal(aJSON, bJSON)
+}
+
+func gzipBytes(data []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(data)
+	zw.Close()
+	return buf.Bytes()
+}
+
+func TestProtobufDecoder_MatchesJSONForFixtures(t *testing.T) {
+	files, err := filepath.Glob("../../test/events/*/*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("nenhum fixture encontrado: %v", err)
+	}
+
+	decode := ProtobufDecoder(testpb.NewEvent)
+
+	for _, file := range files {
+		jsonData, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("erro ao ler %s: %v", file, err)
+		}
+
+		fromJSON, err := Decode(jsonData)
+		if err != nil {
+			t.Fatalf("Decode(%s) erro inesperado: %v", file, err)
+		}
+
+		fromProto, err := decode(toProtobuf(t, jsonData))
+		if err != nil {
+			t.Fatalf("ProtobufDecoder(%s) erro inesperado: %v", file, err)
+		}
+
+		if !sameEvent(fromJSON, fromProto) {
+			t.Errorf("%s: evento decodificado do protobuf difere do JSON", file)
+		}
+	}
+}
+
+func TestHandlerProcessor_ProcessPayload(t *testing.T) {
+	jsonData, err := os.ReadFile("../../test/events/dms-events/vision-drowsiness.json")
+	if err != nil {
+		t.Fatalf("erro ao ler fixture: %v", err)
+	}
+
+	var events []*base.BaseEvent
+	processor := NewHandlerProcessor(func(ctx context.Context, event *base.BaseEvent) error {
+		events = append(events, event)
+		return nil
+	}).WithDecoder(ContentTypeProtobuf, ProtobufDecoder(testpb.NewEvent))
+
+	payloads := []struct {
+		contentType string
+		data        []byte
+	}{
+		{ContentTypeJSON, jsonData},
+		{ContentTypeJSON, gzipBytes(jsonData)},
+		{ContentTypeProtobuf, toProtobuf(t, jsonData)},
+		{ContentTypeProtobuf, gzipBytes(toProtobuf(t, jsonData))},
+	}
+
+	for _, payload := range payloads {
+		if _, err := processor.ProcessPayload(context.Background(), payload.contentType, payload.data); err != nil {
+			t.Fatalf("ProcessPayload(%s) erro inesperado: %v", payload.contentType, err)
+		}
+	}
+
+	for i := 1; i < len(events); i++ {
+		if !sameEvent(events[0], events[i]) {
+			t.Errorf("evento %d difere do evento decodificado do JSON", i)
+		}
+	}
+
+	if processor.Accepts("application/xml") {
+		t.Error("Accepts(application/xml) = true, esperava false")
+	}
+}
+
+func TestHTTPHandler_Protobuf(t *testing.T) {
+	jsonData := readFixture(t, "telemetry-events/telemetry-ignition.json")
+	processor := NewHandlerProcessor(func(ctx context.Context, event *base.BaseEvent) error {
+		return nil
+	}).WithDecoder(ContentTypeProtobuf, ProtobufDecoder(testpb.NewEvent))
+
+	req := post(toProtobuf(t, jsonData))
+	req.Header.Set("Content-Type", ContentTypeProtobuf)
+
+	rec, resp := serve(NewHTTPHandler(processor), req)
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("status = %d, esperava 202", rec.Code)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].ID != "01HXXXXXXXXXXXXXXXXXXXXX" {
+		t.Errorf("resultados = %+v, esperava o evento de ignição", resp.Results)
+	}
+
+	req = post(toProtobuf(t, jsonData))
+	req.Header.Set("Content-Type", ContentTypeProtobuf)
+	if rec, _ := serve(NewHTTPHandler(okProcessor()), req); rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("status = %d, esperava 415 sem decoder protobuf", rec.Code)
+	}
+}
+
+func TestProtobufDecoder_RejectsInvalidPayload(t *testing.T) {
+	decode := ProtobufDecoder(testpb.NewEvent)
+
+	if _, err := decode([]byte{0x0a, 0xff}); !errors.Is(err, ErrParse) {
+		t.Errorf("decode() erro = %v, esperava ErrParse", err)
+	}
+}
+
+func TestHTTPHandler_DecodedPayloadTooLarge(t *testing.T) {
+	payload := gzipBytes(toProtobuf(t, readFixture(t, "telemetry-events/telemetry-ignition.json")))
+
+	processor := NewHandlerProcessor(func(ctx context.Context, event *base.BaseEvent) error {
+		return nil
+	}).WithDecoder(ContentTypeProtobuf, ProtobufDecoder(testpb.NewEvent))
+	processor.MaxDecodedSize = 64
+
+	if _, err := processor.ProcessPayload(context.Background(), ContentTypeProtobuf, payload); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("ProcessPayload() erro = %v, esperava ErrTooLarge", err)
+	}
+
+	req := post(payload)
+	req.Header.Set("Content-Type", ContentTypeProtobuf)
+	if rec, _ := serve(NewHTTPHandler(processor), req); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, esperava 413", rec.Code)
+	}
+}
@@ -0,0 +1,36 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+)
+
+var (
+	ErrParse        = errors.New("webhook: invalid event payload")
+	ErrValidation   = errors.New("webhook: event validation failed")
+	ErrSignature    = errors.New("webhook: invalid signature")
+	ErrBackpressure = errors.New("webhook: processor overloaded")
+	ErrTooLarge     = errors.New("webhook: payload too large")
+	ErrNotFound     = errors.New("webhook: unknown recipient")
+)
+
+func StatusCode(err error) int {
+	switch {
+	case err == nil:
+		return http.StatusAccepted
+	case errors.Is(err, ErrParse):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrSignature):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrBackpressure):
+		return http.StatusTooManyRequests
+	case errors.Is(err, ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusInternalServerError
+	}
+}
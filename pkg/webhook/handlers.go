@@ -0,0 +1,355 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go-eventlib/pkg/types/alert"
+	"go-eventlib/pkg/types/base"
+	"go-eventlib/pkg/types/connection"
+	"go-eventlib/pkg/types/dms"
+	"go-eventlib/pkg/types/driverbehavior"
+	"go-eventlib/pkg/types/hardware"
+	"go-eventlib/pkg/types/order"
+	"go-eventlib/pkg/types/system"
+	"go-eventlib/pkg/types/telemetry"
+	"go-eventlib/pkg/types/vehicle"
+	"go-eventlib/pkg/types/vision"
+)
+
+// Each typed handler exposes Handle, a dispatch.Handler that ignores events
+// outside its context. The specific callback runs first, then the catch-all
+// one (OnDMSAlert, OnAnyAlert, ...); their errors are joined.
+
+func call[E any](ctx context.Context, event E, callbacks ...func(ctx context.Context, event E) error) error {
+	var errs []error
+	for _, callback := range callbacks {
+		if callback != nil {
+			errs = append(errs, callback(ctx, event))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type EventHandler struct {
+	OnOrderReceived func(ctx context.Context, event *order.Event) error
+	OnOrderAck      func(ctx context.Context, event *order.Event) error
+}
+
+func NewEventHandler() *EventHandler { return &EventHandler{} }
+
+func (h *EventHandler) Handle(ctx context.Context, event *base.BaseEvent) error {
+	if event.GetCategory() != "EVENT_CATEGORY_ORDER" {
+		return nil
+	}
+
+	e := order.New(event)
+	if ord := e.GetOrder(); ord != nil && ord.Status == "ORDER_STATUS_ACK" {
+		return call(ctx, e, h.OnOrderAck)
+	}
+	return call(ctx, e, h.OnOrderReceived)
+}
+
+type ConnectionHandler struct {
+	OnWifiConnected    func(ctx context.Context, event *connection.Event) error
+	OnWifiDisconnected func(ctx context.Context, event *connection.Event) error
+	OnSimCardChanged   func(ctx context.Context, event *connection.Event) error
+	OnConnectionError  func(ctx context.Context, event *connection.Event) error
+}
+
+func NewConnectionHandler() *ConnectionHandler { return &ConnectionHandler{} }
+
+func (h *ConnectionHandler) Handle(ctx context.Context, event *base.BaseEvent) error {
+	if event.GetCategory() != "EVENT_CATEGORY_CONNECTION" {
+		return nil
+	}
+
+	e := connection.New(event)
+	switch name := event.GetEventName(); {
+	case name == "WIFI_CONNECTED":
+		return call(ctx, e, h.OnWifiConnected)
+	case name == "WIFI_DISCONNECTED":
+		return call(ctx, e, h.OnWifiDisconnected)
+	case name == "SIMCARD" || strings.HasPrefix(name, "SIM_CARD"):
+		return call(ctx, e, h.OnSimCardChanged)
+	case strings.Contains(name, "ERROR"):
+		return call(ctx, e, h.OnConnectionError)
+	}
+	return nil
+}
+
+type VisionHandler struct {
+	OnFaceDetected     func(ctx context.Context, event *vision.Event) error
+	OnFaceLost         func(ctx context.Context, event *vision.Event) error
+	OnFaceTracked      func(ctx context.Context, event *vision.Event) error
+	OnNoFaceDetected   func(ctx context.Context, event *vision.Event) error
+	OnCameraObstructed func(ctx context.Context, event *vision.Event) error
+	OnVisionAlert      func(ctx context.Context, event *vision.Event) error
+}
+
+func NewVisionHandler() *VisionHandler { return &VisionHandler{} }
+
+func (h *VisionHandler) Handle(ctx context.Context, event *base.BaseEvent) error {
+	if event.GetCategory() != "EVENT_CATEGORY_VISION" {
+		return nil
+	}
+
+	var specific func(ctx context.Context, event *vision.Event) error
+	switch event.GetEventName() {
+	case "FACE_DETECTED":
+		specific = h.OnFaceDetected
+	case "FACE_LOST":
+		specific = h.OnFaceLost
+	case "FACE_TRACKED":
+		specific = h.OnFaceTracked
+	case "NO_FACE_DETECTED":
+		specific = h.OnNoFaceDetected
+	case "CAMERA_OBSTRUCTED":
+		specific = h.OnCameraObstructed
+	}
+	return call(ctx, vision.New(event), specific, h.OnVisionAlert)
+}
+
+// HardwareHandler reacts to device hardware changes, which the platform
+// reports under several categories: reboots as system events, SD card
+// changes as alerts, SIM changes as connection events and vehicle battery
+// changes as telemetry. OnHardwareAlert runs for all of them and for every
+// EVENT_CATEGORY_HEALTH event.
+type HardwareHandler struct {
+	OnDeviceRestart              func(ctx context.Context, event *hardware.Event) error
+	OnVehicleBatteryConnected    func(ctx context.Context, event *hardware.Event) error
+	OnVehicleBatteryDisconnected func(ctx context.Context, event *hardware.Event) error
+	OnSDCardMounted              func(ctx context.Context, event *hardware.Event) error
+	OnSDCardUnmounted            func(ctx context.Context, event *hardware.Event) error
+	OnSimCardInserted            func(ctx context.Context, event *hardware.Event) error
+	OnSimCardRemoved             func(ctx context.Context, event *hardware.Event) error
+	OnHardwareAlert              func(ctx context.Context, event *hardware.Event) error
+}
+
+func NewHardwareHandler() *HardwareHandler { return &HardwareHandler{} }
+
+func (h *HardwareHandler) Handle(ctx context.Context, event *base.BaseEvent) error {
+	var specific func(ctx context.Context, event *hardware.Event) error
+	recognized := true
+
+	switch name := event.GetEventName(); {
+	case name == "REBOOT" || name == "R2_RESTART" || name == "RESTART":
+		specific = h.OnDeviceRestart
+	case name == "SD_CARD_MOUNTED":
+		specific = h.OnSDCardMounted
+	case name == "SD_CARD_UNMOUNTED":
+		specific = h.OnSDCardUnmounted
+	case name == "SIM_CARD_INSERTED":
+		specific = h.OnSimCardInserted
+	case name == "SIM_CARD_REMOVED":
+		specific = h.OnSimCardRemoved
+	case name == "SIMCARD":
+		sim := connection.New(event).GetSimCard()
+		switch {
+		case sim == nil:
+			recognized = false
+		case strings.TrimPrefix(sim.Status, "SIM_CARD_STATUS_") == "ABSENT":
+			specific = h.OnSimCardRemoved
+		default:
+			specific = h.OnSimCardInserted
+		}
+	default:
+		battery := telemetry.New(event).GetBatteryEvent()
+		switch {
+		case battery == nil || battery.Component != "BATTERY_COMPONENT_VEHICLE":
+			recognized = false
+		case battery.Status == "BATTERY_OFFLINE" || battery.Name == "BATTERY_DISCONNECTED":
+			specific = h.OnVehicleBatteryDisconnected
+		case battery.Status == "BATTERY_ONLINE" || battery.Name == "BATTERY_CONNECTED":
+			specific = h.OnVehicleBatteryConnected
+		default:
+			recognized = false
+		}
+	}
+
+	if !recognized && event.GetCategory() != "EVENT_CATEGORY_HEALTH" {
+		return nil
+	}
+	return call(ctx, hardware.New(event), specific, h.OnHardwareAlert)
+}
+
+type SystemHandler struct {
+	OnUploadEvent func(ctx context.Context, event *system.Event) error
+	OnSystemAlert func(ctx context.Context, event *system.Event) error
+}
+
+func NewSystemHandler() *SystemHandler { return &SystemHandler{} }
+
+func (h *SystemHandler) Handle(ctx context.Context, event *base.BaseEvent) error {
+	if event.GetCategory() != "EVENT_CATEGORY_SYSTEM" {
+		return nil
+	}
+
+	e := system.New(event)
+	var specific func(ctx context.Context, event *system.Event) error
+	if e.GetUploadData() != nil {
+		specific = h.OnUploadEvent
+	}
+	return call(ctx, e, specific, h.OnSystemAlert)
+}
+
+// TelemetryHandler matches on the EVENT_SUB_TELEMETRY_* subtype, which
+// ignition events carry under EVENT_CATEGORY_VEHICLE too.
+type TelemetryHandler struct {
+	OnBatteryEvent   func(ctx context.Context, event *telemetry.Event) error
+	OnIgnitionEvent  func(ctx context.Context, event *telemetry.Event) error
+	OnLocationEvent  func(ctx context.Context, event *telemetry.Event) error
+	OnTelemetryEvent func(ctx context.Context, event *telemetry.Event) error
+}
+
+func NewTelemetryHandler() *TelemetryHandler { return &TelemetryHandler{} }
+
+func (h *TelemetryHandler) Handle(ctx context.Context, event *base.BaseEvent) error {
+	sub := event.GetSubType()
+	if event.GetCategory() != "EVENT_CATEGORY_TELEMETRY" && !strings.HasPrefix(string(sub), "EVENT_SUB_TELEMETRY_") {
+		return nil
+	}
+
+	var specific func(ctx context.Context, event *telemetry.Event) error
+	switch sub {
+	case "EVENT_SUB_TELEMETRY_BATTERY":
+		specific = h.OnBatteryEvent
+	case "EVENT_SUB_TELEMETRY_IGNITION":
+		specific = h.OnIgnitionEvent
+	case "EVENT_SUB_TELEMETRY_LOCATION":
+		specific = h.OnLocationEvent
+	}
+	return call(ctx, telemetry.New(event), specific, h.OnTelemetryEvent)
+}
+
+type AlertHandler struct {
+	OnCriticalAlert func(ctx context.Context, event *alert.Event) error
+	OnWarningAlert  func(ctx context.Context, event *alert.Event) error
+	OnInfoAlert     func(ctx context.Context, event *alert.Event) error
+	OnAnyAlert      func(ctx context.Context, event *alert.Event) error
+}
+
+func NewAlertHandler() *AlertHandler { return &AlertHandler{} }
+
+func (h *AlertHandler) Handle(ctx context.Context, event *base.BaseEvent) error {
+	if event.GetCategory() != "EVENT_CATEGORY_ALERT" {
+		return nil
+	}
+
+	e := alert.New(event)
+	var specific func(ctx context.Context, event *alert.Event) error
+	switch e.GetAlertLevel() {
+	case "critical":
+		specific = h.OnCriticalAlert
+	case "warning":
+		specific = h.OnWarningAlert
+	case "info":
+		specific = h.OnInfoAlert
+	}
+	return call(ctx, e, specific, h.OnAnyAlert)
+}
+
+type DMSHandler struct {
+	OnDrowsiness      func(ctx context.Context, event *dms.Event) error
+	OnDrinking        func(ctx context.Context, event *dms.Event) error
+	OnEating          func(ctx context.Context, event *dms.Event) error
+	OnEyeClosure      func(ctx context.Context, event *dms.Event) error
+	OnGazeDistraction func(ctx context.Context, event *dms.Event) error
+	OnGazeFixation    func(ctx context.Context, event *dms.Event) error
+	OnPhone           func(ctx context.Context, event *dms.Event) error
+	OnPoseDistraction func(ctx context.Context, event *dms.Event) error
+	OnSmoking         func(ctx context.Context, event *dms.Event) error
+	OnYawning         func(ctx context.Context, event *dms.Event) error
+	OnDMSAlert        func(ctx context.Context, event *dms.Event) error
+}
+
+func NewDMSHandler() *DMSHandler { return &DMSHandler{} }
+
+func (h *DMSHandler) Handle(ctx context.Context, event *base.BaseEvent) error {
+	if event.GetCategory() != "EVENT_CATEGORY_DMS" {
+		return nil
+	}
+
+	var specific func(ctx context.Context, event *dms.Event) error
+	switch name := event.GetEventName(); {
+	case name == "DROWSINESS":
+		specific = h.OnDrowsiness
+	case name == "DRINKING":
+		specific = h.OnDrinking
+	case name == "EATING":
+		specific = h.OnEating
+	case name == "EYE_CLOSURE":
+		specific = h.OnEyeClosure
+	case name == "GAZE_DISTRACTION":
+		specific = h.OnGazeDistraction
+	case name == "GAZE_FIXATION":
+		specific = h.OnGazeFixation
+	case name == "ON_PHONE":
+		specific = h.OnPhone
+	case strings.HasPrefix(name, "POSE_DISTRACTION"):
+		specific = h.OnPoseDistraction
+	case name == "SMOKING":
+		specific = h.OnSmoking
+	case name == "YAWNING":
+		specific = h.OnYawning
+	}
+	return call(ctx, dms.New(event), specific, h.OnDMSAlert)
+}
+
+type DriverBehaviorHandler struct {
+	OnHarshAcceleration   func(ctx context.Context, event *driverbehavior.Event) error
+	OnHarshBraking        func(ctx context.Context, event *driverbehavior.Event) error
+	OnMaxSpeedFault       func(ctx context.Context, event *driverbehavior.Event) error
+	OnNormalSpeedReturn   func(ctx context.Context, event *driverbehavior.Event) error
+	OnPersistentMaxSpeed  func(ctx context.Context, event *driverbehavior.Event) error
+	OnSharpTurn           func(ctx context.Context, event *driverbehavior.Event) error
+	OnStartOvertaking     func(ctx context.Context, event *driverbehavior.Event) error
+	OnDriverBehaviorAlert func(ctx context.Context, event *driverbehavior.Event) error
+}
+
+func NewDriverBehaviorHandler() *DriverBehaviorHandler { return &DriverBehaviorHandler{} }
+
+func (h *DriverBehaviorHandler) Handle(ctx context.Context, event *base.BaseEvent) error {
+	if event.GetCategory() != "EVENT_CATEGORY_DRIVER_BEHAVIOR" {
+		return nil
+	}
+
+	var specific func(ctx context.Context, event *driverbehavior.Event) error
+	switch event.GetEventName() {
+	case "HARSH_ACCELERATION":
+		specific = h.OnHarshAcceleration
+	case "HARSH_BRAKING":
+		specific = h.OnHarshBraking
+	case "MAX_SPEED_EXCEEDED":
+		specific = h.OnMaxSpeedFault
+	case "RETURN_TO_NORMAL_SPEED":
+		specific = h.OnNormalSpeedReturn
+	case "PERSISTENT_MAX_SPEED":
+		specific = h.OnPersistentMaxSpeed
+	case "HARSH_CORNERING":
+		specific = h.OnSharpTurn
+	case "START_OVERTAKING":
+		specific = h.OnStartOvertaking
+	}
+	return call(ctx, driverbehavior.New(event), specific, h.OnDriverBehaviorAlert)
+}
+
+type VehicleHandler struct {
+	OnIgnitionOff  func(ctx context.Context, event *vehicle.Event) error
+	OnVehicleEvent func(ctx context.Context, event *vehicle.Event) error
+}
+
+func NewVehicleHandler() *VehicleHandler { return &VehicleHandler{} }
+
+func (h *VehicleHandler) Handle(ctx context.Context, event *base.BaseEvent) error {
+	if event.GetCategory() != "EVENT_CATEGORY_VEHICLE" {
+		return nil
+	}
+
+	var specific func(ctx context.Context, event *vehicle.Event) error
+	if telemetry.New(event).GetIgnitionStatus() == telemetry.IgnitionStatusOff {
+		specific = h.OnIgnitionOff
+	}
+	return call(ctx, vehicle.New(event), specific, h.OnVehicleEvent)
+}
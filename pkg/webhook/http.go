@@ -0,0 +1,378 @@
+package webhook
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+
+	"go-eventlib/pkg/types/base"
+)
+
+const (
+	defaultMaxBodySize = 10 << 20
+	ndjsonMediaType    = "application/x-ndjson"
+)
+
+type HTTPOption func(*HTTPHandler)
+
+func WithMaxBodySize(n int64) HTTPOption {
+	return func(h *HTTPHandler) {
+		h.maxBodySize = n
+	}
+}
+
+// WithSignatureVerifier checks each request before any event is processed.
+// body is the raw request body as sent, before gzip decoding. The body is
+// buffered in memory for the verifier; WithHMACSignature streams instead.
+func WithSignatureVerifier(verify func(r *http.Request, body []byte) error) HTTPOption {
+	return func(h *HTTPHandler) {
+		h.verifySignature = verify
+	}
+}
+
+// WithHMACSignature checks the X-Signature header with HMAC-SHA256 while
+// the body is read, spooling it to a temporary file until the signature is
+// verified. secret resolves the key of each request; its error fails the
+// request as is.
+func WithHMACSignature(secret func(r *http.Request) ([]byte, error)) HTTPOption {
+	return func(h *HTTPHandler) {
+		h.hmacSecret = secret
+	}
+}
+
+// WithTraceExtractor derives each request context from its headers, so
+// processing continues an incoming trace. observability.Instrumentation's
+// Extract method fits.
+func WithTraceExtractor(extract func(ctx context.Context, header http.Header) context.Context) HTTPOption {
+	return func(h *HTTPHandler) {
+		h.extractTrace = extract
+	}
+}
+
+func WithErrorHandler(onError func(r *http.Request, err error)) HTTPOption {
+	return func(h *HTTPHandler) {
+		h.onError = onError
+	}
+}
+
+type HTTPHandler struct {
+	processor       Processor
+	maxBodySize     int64
+	verifySignature func(r *http.Request, body []byte) error
+	hmacSecret      func(r *http.Request) ([]byte, error)
+	onError         func(r *http.Request, err error)
+	extractTrace    func(ctx context.Context, header http.Header) context.Context
+}
+
+type EventResult struct {
+	Index  int    `json:"index"`
+	ID     string `json:"id,omitempty"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Response struct {
+	Results []EventResult `json:"results"`
+}
+
+func NewHTTPHandler(processor Processor, opts ...HTTPOption) *HTTPHandler {
+	h := &HTTPHandler{
+		processor:   processor,
+		maxBodySize: defaultMaxBodySize,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.extractTrace != nil {
+		r = r.WithContext(h.extractTrace(r.Context(), r.Header))
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	payloadProcessor, _ := h.processor.(PayloadProcessor)
+	binary := !isJSON(contentType) && payloadProcessor != nil && payloadProcessor.Accepts(contentType)
+
+	if !isJSON(contentType) && !binary {
+		http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, cleanup, err := h.openBody(w, r)
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+	defer cleanup()
+
+	if binary {
+		data, err := io.ReadAll(body)
+		if err != nil {
+			h.fail(w, r, readError(err))
+			return
+		}
+		event, err := payloadProcessor.ProcessPayload(r.Context(), contentType, data)
+		h.respond(w, []EventResult{h.result(r, 0, event, err)})
+		return
+	}
+
+	br := bufio.NewReader(body)
+	first, err := peekNonSpace(br)
+	if err != nil && err != io.EOF {
+		h.fail(w, r, readError(err))
+		return
+	}
+
+	if first == '[' || isNDJSON(contentType) {
+		batch, err := ProcessBatch(r.Context(), br, h.processor)
+		results := h.batchResults(r, batch)
+		if err != nil {
+			if body.err != nil {
+				err = readError(body.err)
+			}
+			results = append(results, h.result(r, len(results), nil, err))
+		}
+		h.respond(w, results)
+		return
+	}
+
+	data, err := io.ReadAll(br)
+	if err != nil {
+		h.fail(w, r, readError(err))
+		return
+	}
+	h.respond(w, []EventResult{h.process(r, 0, bytes.TrimSpace(data))})
+}
+
+// openBody returns the request body as a stream, limited to the max body
+// size and gzip-decoded when Content-Encoding asks for it. Signed bodies
+// are spooled to a temporary file and verified before any event is read,
+// so handlers never see an unverified event.
+func (h *HTTPHandler) openBody(w http.ResponseWriter, r *http.Request) (*trackingReader, func(), error) {
+	var body io.Reader = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+	cleanup := func() {}
+
+	switch {
+	case h.hmacSecret != nil:
+		spool, err := h.spoolSigned(r, body)
+		if err != nil {
+			return nil, nil, err
+		}
+		body = spool
+		cleanup = func() {
+			spool.Close()
+			os.Remove(spool.Name())
+		}
+	case h.verifySignature != nil:
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return nil, nil, readError(err)
+		}
+		if err := h.verifySignature(r, data); err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrSignature, err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	if strings.EqualFold(r.Header.Get("Content-Encoding"), "gzip") {
+		zr, err := gzip.NewReader(body)
+		if err != nil {
+			cleanup()
+			return nil, nil, readError(err)
+		}
+		body = &decodedLimitReader{r: zr, remaining: h.maxBodySize}
+	}
+
+	return &trackingReader{r: body}, cleanup, nil
+}
+
+func (h *HTTPHandler) spoolSigned(r *http.Request, body io.Reader) (*os.File, error) {
+	secret, err := h.hmacSecret(r)
+	if err != nil {
+		return nil, err
+	}
+
+	spool, err := os.CreateTemp("", "webhook-body-*")
+	if err != nil {
+		return nil, err
+	}
+	fail := func(err error) (*os.File, error) {
+		spool.Close()
+		os.Remove(spool.Name())
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	if _, err := io.Copy(spool, io.TeeReader(body, mac)); err != nil {
+		return fail(readError(err))
+	}
+	if err := verifySum(r.Header.Get(SignatureHeader), secret, mac.Sum(nil)); err != nil {
+		return fail(fmt.Errorf("%w: %v", ErrSignature, err))
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return fail(err)
+	}
+	return spool, nil
+}
+
+func readError(err error) error {
+	var maxErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, ErrTooLarge):
+		return err
+	case errors.As(err, &maxErr):
+		return fmt.Errorf("%w: %v", ErrTooLarge, err)
+	}
+	return fmt.Errorf("%w: %v", ErrParse, err)
+}
+
+// trackingReader keeps the first read error so a batch that stops midway
+// can tell a broken body apart from a malformed event.
+type trackingReader struct {
+	r   io.Reader
+	err error
+}
+
+func (t *trackingReader) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	if err != nil && err != io.EOF && t.err == nil {
+		t.err = err
+	}
+	return n, err
+}
+
+type decodedLimitReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *decodedLimitReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, errDecodedTooLarge
+	}
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, errDecodedTooLarge
+	}
+	return n, err
+}
+
+func (h *HTTPHandler) batchResults(r *http.Request, batch *BatchResult) []EventResult {
+	results := make([]EventResult, 0, len(batch.Items))
+	for _, item := range batch.Items {
+		result := EventResult{Index: item.Index, ID: item.ID, Status: StatusCode(item.Err)}
+		if item.Err != nil {
+			result.Error = item.Err.Error()
+			if h.onError != nil {
+				h.onError(r, item.Err)
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func (h *HTTPHandler) process(r *http.Request, index int, data []byte) EventResult {
+	event, err := h.processor.ProcessEvent(r.Context(), data)
+	return h.result(r, index, event, err)
+}
+
+func (h *HTTPHandler) result(r *http.Request, index int, event *base.BaseEvent, err error) EventResult {
+	result := EventResult{Index: index, Status: httpStatus(err)}
+	if event != nil {
+		result.ID = event.GetID()
+	}
+	if err != nil {
+		result.Error = err.Error()
+		if h.onError != nil {
+			h.onError(r, err)
+		}
+	}
+
+	return result
+}
+
+func (h *HTTPHandler) respond(w http.ResponseWriter, results []EventResult) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(overallStatus(results))
+	json.NewEncoder(w).Encode(Response{Results: results})
+}
+
+func (h *HTTPHandler) fail(w http.ResponseWriter, r *http.Request, err error) {
+	if h.onError != nil {
+		h.onError(r, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus(err))
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+func httpStatus(err error) int {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.status
+	}
+	return StatusCode(err)
+}
+
+func overallStatus(results []EventResult) int {
+	if len(results) == 0 {
+		return http.StatusAccepted
+	}
+
+	status := results[0].Status
+	for _, result := range results[1:] {
+		if result.Status != status {
+			return http.StatusMultiStatus
+		}
+	}
+	return status
+}
+
+func isJSON(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") || mediaType == ndjsonMediaType
+}
+
+func isNDJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == ndjsonMediaType
+}
+
+type statusError struct {
+	status int
+	err    error
+}
+
+func (e *statusError) Error() string { return e.err.Error() }
+func (e *statusError) Unwrap() error { return e.err }
@@ -0,0 +1,173 @@
+package webhook
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"go-eventlib/pkg/types/base"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("../../test/events/" + name)
+	if err != nil {
+		t.Fatalf("erro ao ler fixture %s: %v", name, err)
+	}
+	return data
+}
+
+func serve(handler http.Handler, req *http.Request) (*httptest.ResponseRecorder, Response) {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var resp Response
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	return rec, resp
+}
+
+func post(body []byte) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func okProcessor() Processor {
+	return NewHandlerProcessor(func(ctx context.Context, event *base.BaseEvent) error { return nil })
+}
+
+func TestHTTPHandler_SingleEvent(t *testing.T) {
+	rec, resp := serve(NewHTTPHandler(okProcessor()), post(readFixture(t, "dms-events/vision-drowsiness.json")))
+
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("status = %d, esperava 202", rec.Code)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].ID != "01KCHNFZWN4YPSM0A4YFMH09T2" {
+		t.Errorf("resultados = %+v, esperava o evento 01KCHNFZWN4YPSM0A4YFMH09T2", resp.Results)
+	}
+}
+
+func TestHTTPHandler_Array(t *testing.T) {
+	body := "[" + string(readFixture(t, "dms-events/vision-drowsiness.json")) + "," +
+		string(readFixture(t, "vision-basic-events/vision-face-lost.json")) + "]"
+
+	rec, resp := serve(NewHTTPHandler(okProcessor()), post([]byte(body)))
+
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("status = %d, esperava 202", rec.Code)
+	}
+	if len(resp.Results) != 2 || resp.Results[1].Index != 1 {
+		t.Errorf("resultados = %+v, esperava 2 resultados indexados", resp.Results)
+	}
+}
+
+func TestHTTPHandler_ErrorStatusMapping(t *testing.T) {
+	valid := readFixture(t, "dms-events/vision-drowsiness.json")
+
+	tests := []struct {
+		name      string
+		body      []byte
+		handlerFn func(ctx context.Context, event *base.BaseEvent) error
+		want      int
+	}{
+		{"parse", []byte(`{"id":`), nil, http.StatusBadRequest},
+		{"validation", []byte(`{"category":"EVENT_CATEGORY_DMS"}`), nil, http.StatusUnprocessableEntity},
+		{"backpressure", valid, func(ctx context.Context, event *base.BaseEvent) error { return ErrBackpressure }, http.StatusTooManyRequests},
+		{"handler", valid, func(ctx context.Context, event *base.BaseEvent) error { return errors.New("falha") }, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn := tt.handlerFn
+			if fn == nil {
+				fn = func(ctx context.Context, event *base.BaseEvent) error { return nil }
+			}
+
+			rec, resp := serve(NewHTTPHandler(NewHandlerProcessor(fn)), post(tt.body))
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, esperava %d", rec.Code, tt.want)
+			}
+			if len(resp.Results) != 1 || resp.Results[0].Status != tt.want || resp.Results[0].Error == "" {
+				t.Errorf("resultados = %+v, esperava status %d com erro", resp.Results, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_PartialBatch(t *testing.T) {
+	body := "[" + string(readFixture(t, "dms-events/vision-drowsiness.json")) + `,{"id":"x"}]`
+
+	rec, resp := serve(NewHTTPHandler(okProcessor()), post([]byte(body)))
+
+	if rec.Code != http.StatusMultiStatus {
+		t.Errorf("status = %d, esperava 207", rec.Code)
+	}
+	if len(resp.Results) != 2 || resp.Results[0].Status != http.StatusAccepted || resp.Results[1].Status != http.StatusUnprocessableEntity {
+		t.Errorf("resultados = %+v, esperava [202 422]", resp.Results)
+	}
+}
+
+func TestHTTPHandler_Signature(t *testing.T) {
+	handler := NewHTTPHandler(okProcessor(), WithSignatureVerifier(func(r *http.Request, body []byte) error {
+		if r.Header.Get("X-Signature") != "valid" {
+			return errors.New("assinatura não confere")
+		}
+		return nil
+	}))
+
+	rec, _ := serve(handler, post(readFixture(t, "dms-events/vision-drowsiness.json")))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, esperava 401", rec.Code)
+	}
+
+	req := post(readFixture(t, "dms-events/vision-drowsiness.json"))
+	req.Header.Set("X-Signature", "valid")
+	if rec, _ := serve(handler, req); rec.Code != http.StatusAccepted {
+		t.Errorf("status = %d, esperava 202", rec.Code)
+	}
+}
+
+func TestHTTPHandler_Gzip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(readFixture(t, "telemetry-events/telemetry-ignition.json"))
+	zw.Close()
+
+	req := post(buf.Bytes())
+	req.Header.Set("Content-Encoding", "gzip")
+
+	rec, resp := serve(NewHTTPHandler(okProcessor()), req)
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("status = %d, esperava 202", rec.Code)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].ID != "01HXXXXXXXXXXXXXXXXXXXXX" {
+		t.Errorf("resultados = %+v, esperava o evento de ignição", resp.Results)
+	}
+}
+
+func TestHTTPHandler_RequestChecks(t *testing.T) {
+	handler := NewHTTPHandler(okProcessor(), WithMaxBodySize(64))
+
+	rec, _ := serve(handler, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET: status = %d, esperava 405", rec.Code)
+	}
+
+	req := post([]byte(`{}`))
+	req.Header.Set("Content-Type", "text/plain")
+	if rec, _ := serve(handler, req); rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("text/plain: status = %d, esperava 415", rec.Code)
+	}
+
+	if rec, _ := serve(handler, post([]byte(strings.Repeat(" ", 128)))); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("corpo grande: status = %d, esperava 413", rec.Code)
+	}
+}
@@ -0,0 +1,160 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"go-eventlib/pkg/dispatch"
+	"go-eventlib/pkg/sink"
+	"go-eventlib/pkg/types/base"
+)
+
+type Processor interface {
+	ProcessEvent(ctx context.Context, data []byte) (*base.BaseEvent, error)
+}
+
+type PayloadProcessor interface {
+	Processor
+	Accepts(contentType string) bool
+	ProcessPayload(ctx context.Context, contentType string, data []byte) (*base.BaseEvent, error)
+}
+
+// Processing stages reported to an Observer.
+const (
+	StageParse    = "parse"
+	StageValidate = "validate"
+	StageDispatch = "dispatch"
+)
+
+// Observer is notified around the parse, validate and dispatch stages of
+// every event. The returned func ends the stage with the event, once known,
+// and the stage error. observability.Instrumentation implements it.
+type Observer interface {
+	ObserveStage(ctx context.Context, stage string) (context.Context, func(event *base.BaseEvent, err error))
+}
+
+// RuleMatcher evaluates alerting rules against an event, runs their actions
+// and returns the IDs of the matching rules. rules.Engine implements it.
+type RuleMatcher interface {
+	MatchRules(ctx context.Context, event *base.BaseEvent) ([]string, error)
+}
+
+type HandlerProcessor struct {
+	Handler        dispatch.Handler
+	MaxDecodedSize int64
+	Sink           sink.EventSink
+	Rules          RuleMatcher
+	OnRuleMatch    func(ctx context.Context, ruleID string, event *base.BaseEvent)
+
+	decoders map[string]Decoder
+	observer Observer
+}
+
+func NewHandlerProcessor(handler dispatch.Handler) *HandlerProcessor {
+	return &HandlerProcessor{
+		Handler:        handler,
+		MaxDecodedSize: defaultMaxBodySize,
+		decoders:       map[string]Decoder{ContentTypeJSON: Decode},
+	}
+}
+
+func (p *HandlerProcessor) WithDecoder(contentType string, decoder Decoder) *HandlerProcessor {
+	p.decoders[mediaType(contentType)] = decoder
+	return p
+}
+
+func (p *HandlerProcessor) WithObserver(observer Observer) *HandlerProcessor {
+	p.observer = observer
+	return p
+}
+
+func (p *HandlerProcessor) Accepts(contentType string) bool {
+	_, ok := p.decoders[mediaType(contentType)]
+	return ok
+}
+
+func (p *HandlerProcessor) ProcessEvent(ctx context.Context, data []byte) (*base.BaseEvent, error) {
+	return p.ProcessPayload(ctx, ContentTypeJSON, data)
+}
+
+func (p *HandlerProcessor) ProcessPayload(ctx context.Context, contentType string, data []byte) (*base.BaseEvent, error) {
+	decode, ok := p.decoders[mediaType(contentType)]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported content type %q", ErrParse, contentType)
+	}
+
+	_, end := p.observe(ctx, StageParse)
+	event, err := p.parse(decode, data)
+	end(event, err)
+	if err != nil {
+		return event, err
+	}
+
+	_, end = p.observe(ctx, StageValidate)
+	err = Validate(event)
+	end(event, err)
+	if err != nil {
+		return event, err
+	}
+
+	dispatchCtx, end := p.observe(ctx, StageDispatch)
+	err = p.dispatch(dispatchCtx, event)
+	end(event, err)
+	return event, err
+}
+
+func (p *HandlerProcessor) parse(decode Decoder, data []byte) (*base.BaseEvent, error) {
+	if isGzip(data) {
+		decoded, err := gunzip(data, p.MaxDecodedSize)
+		if err != nil {
+			return nil, err
+		}
+		data = decoded
+	}
+	return decode(data)
+}
+
+// dispatch runs the rules and the handler, then writes the sink once they
+// succeeded, so a failed event is not archived and a redelivery after a
+// handler failure is not written twice.
+func (p *HandlerProcessor) dispatch(ctx context.Context, event *base.BaseEvent) error {
+	if p.Rules != nil {
+		ruleIDs, err := p.Rules.MatchRules(ctx, event)
+		if p.OnRuleMatch != nil {
+			for _, ruleID := range ruleIDs {
+				p.OnRuleMatch(ctx, ruleID, event)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := p.Handler(ctx, event); err != nil {
+		return err
+	}
+
+	if p.Sink != nil {
+		return p.Sink.Write(ctx, event)
+	}
+	return nil
+}
+
+func (p *HandlerProcessor) observe(ctx context.Context, stage string) (context.Context, func(*base.BaseEvent, error)) {
+	if p.observer == nil {
+		return ctx, func(*base.BaseEvent, error) {}
+	}
+	return p.observer.ObserveStage(ctx, stage)
+}
+
+func Validate(event *base.BaseEvent) error {
+	switch {
+	case event.ID == "":
+		return fmt.Errorf("%w: missing id", ErrValidation)
+	case event.Category == "":
+		return fmt.Errorf("%w: missing category", ErrValidation)
+	case event.CreatedAt.IsZero():
+		return fmt.Errorf("%w: missing created_at", ErrValidation)
+	}
+	return nil
+}
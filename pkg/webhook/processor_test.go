@@ -0,0 +1,157 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-eventlib/pkg/rules"
+	"go-eventlib/pkg/types/base"
+)
+
+func TestDecodeAndValidate_Fixtures(t *testing.T) {
+	files, err := filepath.Glob("../../test/events/*/*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("nenhum fixture encontrado: %v", err)
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("erro ao ler %s: %v", file, err)
+		}
+
+		event, err := Decode(data)
+		if err != nil {
+			t.Errorf("Decode(%s) erro inesperado: %v", file, err)
+			continue
+		}
+		if err := Validate(event); err != nil {
+			t.Errorf("Validate(%s) erro inesperado: %v", file, err)
+		}
+	}
+}
+
+func TestDecode_InvalidJSON(t *testing.T) {
+	if _, err := Decode([]byte(`{"id":`)); !errors.Is(err, ErrParse) {
+		t.Errorf("Decode() = %v, esperava ErrParse", err)
+	}
+}
+
+func TestValidate_MissingFields(t *testing.T) {
+	if err := Validate(&base.BaseEvent{Category: "EVENT_CATEGORY_DMS"}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Validate() = %v, esperava ErrValidation", err)
+	}
+}
+
+func TestHandlerProcessor_ProcessEvent(t *testing.T) {
+	data, err := os.ReadFile("../../test/events/dms-events/vision-drowsiness.json")
+	if err != nil {
+		t.Fatalf("erro ao ler fixture: %v", err)
+	}
+
+	var got *base.BaseEvent
+	processor := NewHandlerProcessor(func(ctx context.Context, event *base.BaseEvent) error {
+		got = event
+		return nil
+	})
+
+	event, err := processor.ProcessEvent(context.Background(), data)
+	if err != nil {
+		t.Fatalf("ProcessEvent() erro inesperado: %v", err)
+	}
+	if got != event {
+		t.Error("handler não recebeu o evento processado")
+	}
+	if event.GetEventName() != "DROWSINESS" {
+		t.Errorf("GetEventName() = %s, esperava DROWSINESS", event.GetEventName())
+	}
+}
+
+type memorySink struct {
+	events []*base.BaseEvent
+	err    error
+}
+
+func (s *memorySink) Write(ctx context.Context, event *base.BaseEvent) error {
+	s.events = append(s.events, event)
+	return s.err
+}
+
+func (s *memorySink) Flush(ctx context.Context) error { return nil }
+func (s *memorySink) Close() error                    { return nil }
+
+func TestHandlerProcessor_WritesToSink(t *testing.T) {
+	data, err := os.ReadFile("../../test/events/dms-events/vision-drowsiness.json")
+	if err != nil {
+		t.Fatalf("erro ao ler fixture: %v", err)
+	}
+
+	handled := false
+	s := &memorySink{}
+	processor := NewHandlerProcessor(func(ctx context.Context, event *base.BaseEvent) error {
+		handled = true
+		return nil
+	})
+	processor.Sink = s
+
+	event, err := processor.ProcessEvent(context.Background(), data)
+	if err != nil {
+		t.Fatalf("ProcessEvent() erro inesperado: %v", err)
+	}
+	if len(s.events) != 1 || s.events[0] != event {
+		t.Errorf("sink recebeu %d eventos, esperava 1", len(s.events))
+	}
+	if !handled {
+		t.Error("handler não foi chamado")
+	}
+
+	s.err = errors.New("sink indisponível")
+	if _, err := processor.ProcessEvent(context.Background(), data); !errors.Is(err, s.err) {
+		t.Errorf("ProcessEvent() = %v, esperava erro do sink", err)
+	}
+
+	s.err = nil
+	s.events = nil
+	failing := NewHandlerProcessor(func(ctx context.Context, event *base.BaseEvent) error {
+		return errors.New("falha no handler")
+	})
+	failing.Sink = s
+	if _, err := failing.ProcessEvent(context.Background(), data); err == nil {
+		t.Error("ProcessEvent() esperava erro do handler")
+	}
+	if len(s.events) != 0 {
+		t.Errorf("sink recebeu %d eventos, esperava nenhum após falha do handler", len(s.events))
+	}
+}
+
+func TestHandlerProcessor_ReportsRuleMatches(t *testing.T) {
+	data, err := os.ReadFile("../../test/events/dms-events/vision-drowsiness.json")
+	if err != nil {
+		t.Fatalf("erro ao ler fixture: %v", err)
+	}
+
+	engine, err := rules.NewEngine([]rules.Rule{
+		{ID: "drowsiness", Expression: `event.category == "DMS" && event.name == "DROWSINESS"`},
+		{ID: "phone", Expression: `event.name == "ON_PHONE"`},
+	}, rules.Config{})
+	if err != nil {
+		t.Fatalf("NewEngine() erro inesperado: %v", err)
+	}
+
+	var matched []string
+	processor := NewHandlerProcessor(func(ctx context.Context, event *base.BaseEvent) error { return nil })
+	processor.Rules = engine
+	processor.OnRuleMatch = func(ctx context.Context, ruleID string, event *base.BaseEvent) {
+		matched = append(matched, ruleID)
+	}
+
+	if _, err := processor.ProcessEvent(context.Background(), data); err != nil {
+		t.Fatalf("ProcessEvent() erro inesperado: %v", err)
+	}
+	if len(matched) != 1 || matched[0] != "drowsiness" {
+		t.Errorf("regras reportadas = %v, esperava [drowsiness]", matched)
+	}
+}
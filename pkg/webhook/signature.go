@@ -0,0 +1,55 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const SignatureHeader = "X-Signature"
+
+const signaturePrefix = "sha256="
+
+// Sign returns the HMAC-SHA256 of body as "sha256=<hex>", the format
+// expected in the X-Signature header. The signature covers the request body
+// exactly as sent: for a gzip Content-Encoding that is the compressed bytes,
+// not the decompressed payload.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+func VerifySignature(signature string, secret, body []byte) error {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return verifySum(signature, secret, mac.Sum(nil))
+}
+
+func verifySum(signature string, secret, sum []byte) error {
+	if signature == "" {
+		return errors.New("missing signature")
+	}
+	if len(secret) == 0 {
+		return errors.New("no signing secret configured")
+	}
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return errors.New("unsupported signature scheme")
+	}
+	if !hmac.Equal([]byte(signature), []byte(signaturePrefix+hex.EncodeToString(sum))) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+// HMACVerifier checks the X-Signature header against the raw request body,
+// for use with WithSignatureVerifier. WithHMACSignature does the same check
+// without buffering the body.
+func HMACVerifier(secret []byte) func(r *http.Request, body []byte) error {
+	return func(r *http.Request, body []byte) error {
+		return VerifySignature(r.Header.Get(SignatureHeader), secret, body)
+	}
+}
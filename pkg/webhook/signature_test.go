@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"go-eventlib/pkg/types/base"
+)
+
+func TestVerifySignature(t *testing.T) {
+	secret := []byte("segredo")
+	body := []byte(`{"id":"1"}`)
+
+	tests := []struct {
+		name      string
+		signature string
+		secret    []byte
+		wantErr   bool
+	}{
+		{"válida", Sign(secret, body), secret, false},
+		{"ausente", "", secret, true},
+		{"outro segredo", Sign([]byte("outro"), body), secret, true},
+		{"sem segredo", Sign(secret, body), nil, true},
+		{"esquema", "sha1=abc", secret, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifySignature(tt.signature, tt.secret, body); (err != nil) != tt.wantErr {
+				t.Errorf("VerifySignature() erro = %v, esperava erro %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_HMACVerifier(t *testing.T) {
+	secret := []byte("segredo")
+	body := readFixture(t, "dms-events/vision-drowsiness.json")
+	handler := NewHTTPHandler(okProcessor(), WithSignatureVerifier(HMACVerifier(secret)))
+
+	req := post(body)
+	req.Header.Set(SignatureHeader, Sign(secret, body))
+	if rec, _ := serve(handler, req); rec.Code != http.StatusAccepted {
+		t.Errorf("status = %d, esperava 202", rec.Code)
+	}
+
+	req = post(body)
+	req.Header.Set(SignatureHeader, Sign([]byte("outro"), body))
+	if rec, _ := serve(handler, req); rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, esperava 401", rec.Code)
+	}
+}
+
+func TestHTTPHandler_SignatureCoversBodyAsSent(t *testing.T) {
+	secret := []byte("segredo")
+	payload := readFixture(t, "dms-events/vision-drowsiness.json")
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(payload)
+	zw.Close()
+	compressed := buf.Bytes()
+
+	handler := NewHTTPHandler(okProcessor(), WithSignatureVerifier(HMACVerifier(secret)))
+
+	tests := []struct {
+		name   string
+		signed []byte
+		want   int
+	}{
+		{"corpo comprimido", compressed, http.StatusAccepted},
+		{"payload descomprimido", payload, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := post(compressed)
+			req.Header.Set("Content-Encoding", "gzip")
+			req.Header.Set(SignatureHeader, Sign(secret, tt.signed))
+			if rec, _ := serve(handler, req); rec.Code != tt.want {
+				t.Errorf("status = %d, esperava %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_HMACSignatureStreams(t *testing.T) {
+	secret := []byte("segredo")
+	payload := readFixture(t, "dms-events/vision-drowsiness.json")
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(payload)
+	zw.Close()
+	compressed := buf.Bytes()
+
+	errUnknown := errors.New("conta desconhecida")
+	calls := 0
+	handler := NewHTTPHandler(NewHandlerProcessor(func(ctx context.Context, event *base.BaseEvent) error {
+		calls++
+		return nil
+	}), WithHMACSignature(func(r *http.Request) ([]byte, error) {
+		if r.Header.Get("X-Account") == "" {
+			return nil, &statusError{status: http.StatusNotFound, err: errUnknown}
+		}
+		return secret, nil
+	}))
+
+	tests := []struct {
+		name      string
+		body      []byte
+		gzip      bool
+		signature string
+		account   string
+		want      int
+		wantCalls int
+	}{
+		{"válida", payload, false, Sign(secret, payload), "acc", http.StatusAccepted, 1},
+		{"gzip", compressed, true, Sign(secret, compressed), "acc", http.StatusAccepted, 1},
+		{"inválida", payload, false, Sign([]byte("outro"), payload), "acc", http.StatusUnauthorized, 0},
+		{"sem segredo", payload, false, Sign(secret, payload), "", http.StatusNotFound, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			req := post(tt.body)
+			if tt.gzip {
+				req.Header.Set("Content-Encoding", "gzip")
+			}
+			req.Header.Set(SignatureHeader, tt.signature)
+			req.Header.Set("X-Account", tt.account)
+
+			if rec, _ := serve(handler, req); rec.Code != tt.want {
+				t.Errorf("status = %d, esperava %d", rec.Code, tt.want)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("handler chamado %d vezes, esperava %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}